@@ -2,111 +2,485 @@ package kowalski
 
 import (
 	"context"
-	"sort"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
 	"strings"
-
-	"golang.org/x/exp/slices"
 )
 
-// Anagram finds all single-word anagrams of the given word, expanding '?' as a single wildcard character
-func Anagram(ctx context.Context, checker *SpellChecker, word string) ([]string, error) {
-	return anagram(ctx, checker, word, false, 0)
+// Anagram finds all single-word anagrams of the given word, expanding '?' as a single wildcard character. The word
+// may be an anagram expression that adds or removes letters, or requires a word to be contained in each result (see
+// ParseAnagramExpression).
+func Anagram(ctx context.Context, checker Dictionary, word string) ([]string, error) {
+	if _, err := ParseAnagramExpression(word); err != nil {
+		return nil, err
+	}
+	return collect(ctx, AnagramSeq(ctx, checker, word))
+}
+
+// AnagramSeq returns an iterator over all single-word anagrams of the given word, expanding '?' as a single wildcard
+// character. Anagrams are yielded as they are found; iteration stops early if the context is cancelled, in which
+// case callers should check ctx.Err(). An invalid anagram expression yields no results.
+func AnagramSeq(ctx context.Context, checker Dictionary, word string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if expression, err := ParseAnagramExpression(word); err == nil {
+			anagram(ctx, checker, expression)(yield)
+		}
+	}
 }
 
 // MultiAnagram finds all single- and multi-word anagrams of the given word, expanding '?' as a single wildcard
-// character. To avoid duplicates, words are sorted lexicographically (i.e., "a ball" will be returned and "ball a"
-// will not).
-func MultiAnagram(ctx context.Context, checker *SpellChecker, word string) ([]string, error) {
-	// TODO: Allow configuring of the min length
-	return anagram(ctx, checker, word, true, 2)
+// character. To avoid duplicates, words are sorted lexicographically (i.e., "a ball" will be returned and "ball a" will
+// not). The results can be constrained using options; if no minimum word length is given, words must be at least two
+// letters long. If an enumeration is given, the words are instead returned in the order and with the lengths that it
+// specifies, and an error is returned if the letters don't fit it. The word may be an anagram expression (see
+// ParseAnagramExpression); any words it requires are added to the options' required words.
+func MultiAnagram(ctx context.Context, checker Dictionary, word string, options MultiWordOptions) ([]string, error) {
+	if _, err := parseMultiAnagram(word, options); err != nil {
+		return nil, err
+	}
+	return collect(ctx, MultiAnagramSeq(ctx, checker, word, options))
 }
 
-func anagram(ctx context.Context, checker *SpellChecker, word string, multiWord bool, minLength int) ([]string, error) {
-	var (
-		res        []string
-		swapBefore = len(word)
-	)
+// parseMultiAnagram parses the anagram expression for MultiAnagram, checking that its letters fit the enumeration in
+// the options (if any).
+func parseMultiAnagram(word string, options MultiWordOptions) (AnagramExpression, error) {
+	expression, err := ParseAnagramExpression(word)
+	if err != nil {
+		return AnagramExpression{}, err
+	}
+
+	if err := options.checkEnumeration(word, len(expression.Letters), false); err != nil {
+		return AnagramExpression{}, err
+	}
+	return expression, nil
+}
 
-	sortedWord := func(w string) string {
-		s := strings.Split(strings.ToLower(w), "")
-		sort.Strings(s)
-		return strings.Join(s, "")
-	}(word)
+// MultiAnagramSeq returns an iterator over the results of MultiAnagram. Anagrams are yielded as they are found;
+// iteration stops early if the context is cancelled, in which case callers should check ctx.Err(). An invalid
+// anagram expression yields no results.
+func MultiAnagramSeq(ctx context.Context, checker Dictionary, word string, options MultiWordOptions) iter.Seq[string] {
+	if options.MinWordLength == 0 {
+		options.MinWordLength = 2
+	}
 
-	for w := []byte(sortedWord); w != nil; w = permute(w, swapBefore+1) {
-		matches, count, err := findMatch(ctx, checker, string(w), multiWord, minLength)
+	return func(yield func(string) bool) {
+		expression, err := parseMultiAnagram(word, options)
 		if err != nil {
-			return nil, err
+			return
 		}
 
-		if len(matches) > 0 {
-			res = append(res, matches...)
-			swapBefore = len(word)
+		options.Required = append(slices.Clone(options.Required), expression.Contains...)
+		if len(options.Required) > 0 && !options.enumerated() {
+			requiredAnagram(ctx, checker, expression.pool(), options)(yield)
 		} else {
-			swapBefore = count
+			multiAnagram(ctx, checker, expression.pool(), options)(yield)
 		}
 	}
+}
+
+// SubAnagramOptions constrains the results of SubAnagram.
+type SubAnagramOptions struct {
+	// MinLength is the minimum length of each word. If zero, words must be at least two letters long.
+	MinLength int
+	// Required lists letters that every word must use. Letters may be repeated to require them multiple times.
+	Required string
+}
+
+// SubAnagram finds all words that can be made from some or all of the given letters, expanding '?' as a single
+// wildcard character. Use GroupByLength to group the results by the number of letters used. The letters may be an
+// anagram expression (see ParseAnagramExpression), in which case each word must contain the expression's words.
+func SubAnagram(ctx context.Context, checker Dictionary, letters string, options SubAnagramOptions) ([]string, error) {
+	if _, err := ParseAnagramExpression(letters); err != nil {
+		return nil, err
+	}
+	return collect(ctx, SubAnagramSeq(ctx, checker, letters, options))
+}
+
+// SubAnagramSeq returns an iterator over the results of SubAnagram. Words are yielded as they are found; iteration
+// stops early if the context is cancelled, in which case callers should check ctx.Err(). An invalid anagram expression
+// yields no results.
+func SubAnagramSeq(ctx context.Context, checker Dictionary, letters string, options SubAnagramOptions) iter.Seq[string] {
+	minLength := options.MinLength
+	if minLength == 0 {
+		minLength = 2
+	}
+
+	required, ok := newLetterPool(options.Required)
+	return func(yield func(string) bool) {
+		expression, err := ParseAnagramExpression(letters)
+		if !ok || err != nil || required.wildcards > 0 {
+			return
+		}
+
+		pool := expression.pool()
+		findSubAnagrams(ctx, checker, pool, pool.size, func(word string) bool {
+			return len(word) >= minLength && required.within(word) && expression.containedIn(word)
+		}, yield)
+	}
+}
+
+// ParseSubAnagramOptions splits options in the form "key=value" from the rest of the input, returning the remaining
+// fields joined together along with the parsed options. The supported keys are:
+//
+//   - min: the minimum length of each word
+//   - using: letters that each word must use (may be repeated)
+//
+// Fields of the form "with=word" are part of the anagram expression (see ParseAnagramExpression), so are left in the
+// returned input. For example, "letters min=5 using=x with=set" returns "letters with=set" with a MinLength of 5 and
+// Required set to "x".
+func ParseSubAnagramOptions(input string) (string, SubAnagramOptions, error) {
+	var (
+		options SubAnagramOptions
+		rest    []string
+		words   []string
+	)
+
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
 
-	if multiWord {
-		res = onlyAscendingWords(res)
+		switch strings.ToLower(key) {
+		case "min":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return "", SubAnagramOptions{}, fmt.Errorf("option %s requires a non-negative number, got %q", key, value)
+			}
+			options.MinLength = n
+		case "using":
+			if _, ok := newLetterPool(value); !ok || value == "" || strings.Contains(value, "?") {
+				return "", SubAnagramOptions{}, fmt.Errorf("option %s requires letters, got %q", key, value)
+			}
+			options.Required += strings.ToLower(value)
+		case "with":
+			words = append(words, field)
+		default:
+			return "", SubAnagramOptions{}, fmt.Errorf("unknown option: %s", key)
+		}
 	}
 
-	sort.Strings(res)
-	return slices.Compact(res), nil
+	return strings.Join(append([]string{strings.Join(rest, "")}, words...), " "), options, nil
+}
+
+// LengthGroup is a set of words that all have the same length.
+type LengthGroup struct {
+	Length int      `json:"length"`
+	Words  []string `json:"words"`
+}
+
+// GroupByLength groups the given words by their length, longest first. The order of words within each group is
+// preserved.
+func GroupByLength(words []string) []LengthGroup {
+	var groups []LengthGroup
+	for _, word := range words {
+		i, found := slices.BinarySearchFunc(groups, len(word), func(group LengthGroup, length int) int {
+			return length - group.Length
+		})
+		if !found {
+			groups = slices.Insert(groups, i, LengthGroup{Length: len(word)})
+		}
+		groups[i].Words = append(groups[i].Words, word)
+	}
+	return groups
 }
 
-// onlyAscendingWords returns a slice that contains all the entries from input that meet the following criteria:
-// * the entry is a single word
-// * the entry is multiple-space separated words in increasing lexicographical order
-func onlyAscendingWords(input []string) []string {
-	var filtered []string
-	for i := range input {
-		parts := strings.Split(input[i], " ")
-		if len(parts) > 1 {
-			last := parts[0]
-			valid := true
-			for _, p := range parts[1:] {
-				if strings.Compare(last, p) == 1 {
-					valid = false
-					break
+// requiredAnagram finds multi-word anagrams that include all the required words given in the options. The letters
+// of the required words are removed from the input (falling back to wildcards if necessary), and the remaining
+// letters anagrammed with correspondingly adjusted word count constraints.
+func requiredAnagram(ctx context.Context, checker Dictionary, pool letterPool, options MultiWordOptions) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		required := make([]string, len(options.Required))
+		for i := range options.Required {
+			required[i] = strings.ToLower(options.Required[i])
+		}
+
+		remaining, ok := pool.without(strings.Join(required, ""))
+		if !ok {
+			return
+		}
+
+		options.Required = nil
+		options.MinWords = max(options.MinWords-len(required), 0)
+		if options.MaxWords > 0 {
+			options.MaxWords -= len(required)
+			if options.MaxWords < 1 {
+				if options.MaxWords == 0 && remaining.size == 0 {
+					yield(strings.Join(sortedWords(required), " "))
 				}
+				return
 			}
-			if valid {
-				filtered = append(filtered, input[i])
+		}
+
+		if remaining.size == 0 {
+			if options.MinWords == 0 {
+				yield(strings.Join(sortedWords(required), " "))
+			}
+			return
+		}
+
+		for match := range multiAnagram(ctx, checker, remaining, options) {
+			if !yield(strings.Join(sortedWords(append(strings.Split(match, " "), required...)), " ")) {
+				return
 			}
-		} else {
-			filtered = append(filtered, input[i])
 		}
 	}
-	return filtered
 }
 
-// permute returns the next permutation of the given input, in lexicographical order.
-// swapBefore can be used to force a swap within a certain number characters (i.e., skip all permutations that
-// affect characters after the one with index swapBefore).
-func permute(input []byte, swapBefore int) []byte {
-	if swapBefore < len(input)-1 {
-		input = append(input[0:swapBefore], func(w []byte) []byte {
-			s := strings.Split(string(w), "")
-			sort.Strings(s)
-			return reverse([]byte(strings.Join(s, "")), 0)
-		}(input[swapBefore:])...)
+// sortedWords returns a sorted copy of the given words.
+func sortedWords(words []string) []string {
+	res := slices.Clone(words)
+	slices.Sort(res)
+	return res
+}
+
+// letterPool is a multiset of letters and wildcards available to make anagrams from.
+type letterPool struct {
+	letters   [26]int
+	wildcards int
+	size      int
+}
+
+// newLetterPool creates a pool from the letters and '?' wildcards in the input. If the input contains any other
+// characters, false is returned as they could never form part of a word.
+func newLetterPool(input string) (letterPool, bool) {
+	var pool letterPool
+	for _, c := range []byte(strings.ToLower(input)) {
+		switch {
+		case c >= 'a' && c <= 'z':
+			pool.letters[c-'a']++
+		case c == '?':
+			pool.wildcards++
+		default:
+			return letterPool{}, false
+		}
+		pool.size++
 	}
+	return pool, true
+}
 
-	k, l := -1, -1
-	for i := range input {
-		if i+1 < len(input) && input[i] < input[i+1] {
-			k = i
-			l = -1
-		} else if k >= 0 && input[k] < input[i] {
-			l = i
+// String returns the letters in the pool in alphabetical order, followed by a '?' for each wildcard.
+func (p letterPool) String() string {
+	b := &strings.Builder{}
+	for i, n := range p.letters {
+		for range n {
+			b.WriteByte('a' + byte(i))
 		}
 	}
+	b.WriteString(strings.Repeat("?", p.wildcards))
+	return b.String()
+}
+
+// within determines whether all the letters in the pool are used by the given word.
+func (p letterPool) within(word string) bool {
+	counts := p.letters
+	for i := range word {
+		if word[i] >= 'a' && word[i] <= 'z' && counts[word[i]-'a'] > 0 {
+			counts[word[i]-'a']--
+		}
+	}
+	return counts == [26]int{}
+}
 
-	if k == -1 {
-		return nil
+// without returns a copy of the pool with the letters of the given word removed, using wildcards in place of any
+// letters that aren't available. If there aren't enough letters or wildcards, false is returned.
+func (p letterPool) without(word string) (letterPool, bool) {
+	for i := range word {
+		c := word[i]
+		switch {
+		case c >= 'a' && c <= 'z' && p.letters[c-'a'] > 0:
+			p.letters[c-'a']--
+		case p.wildcards > 0:
+			p.wildcards--
+		default:
+			return letterPool{}, false
+		}
+		p.size--
+	}
+	return p, true
+}
+
+// anagram finds single-word anagrams of the letters in the expression that contain all its required words.
+func anagram(ctx context.Context, checker Dictionary, expression AnagramExpression) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		pool := expression.pool()
+		if pool.size == 0 {
+			return
+		}
+
+		findSubAnagrams(ctx, checker, pool, pool.size, func(word string) bool {
+			return len(word) == pool.size && expression.containedIn(word)
+		}, yield)
+	}
+}
+
+// multiAnagram finds multi-word anagrams of the letters in the pool. It first finds every word that can be made from
+// some of the letters, and then searches for combinations of those words that use all the letters. For results
+// without an enumeration, words are required to be in lexicographical order to avoid returning the same set of
+// words in different orders.
+func multiAnagram(ctx context.Context, checker Dictionary, pool letterPool, options MultiWordOptions) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		maxLength := pool.size
+		if options.enumerated() {
+			maxLength = slices.Max(options.Enumeration.lengths)
+		} else if options.MaxWordLength > 0 {
+			maxLength = min(options.MaxWordLength, maxLength)
+		}
+
+		// Candidates are found in lexicographical order, so each list will be sorted.
+		byLength := make([][]string, maxLength+1)
+		findSubAnagrams(ctx, checker, pool, maxLength, func(word string) bool {
+			if options.enumerated() {
+				return slices.Contains(options.Enumeration.lengths, len(word))
+			}
+			return options.allowsWord(0, len(word))
+		}, func(word string) bool {
+			byLength[len(word)] = append(byLength[len(word)], word)
+			return true
+		})
+
+		type deadEnd struct {
+			pool  letterPool
+			index int
+		}
+
+		var (
+			words    []string
+			ordered  = !options.enumerated()
+			found    int
+			combine  func(pool letterPool) bool
+			memoise  = len(options.Required) == 0
+			deadEnds = make(map[deadEnd]string)
+		)
+
+		// Remember pools that can't be completed, along with the previous word at the time; any later attempt to
+		// complete the same pool at the same position with a word that sorts after it will fail in the same way.
+		combine = func(pool letterPool) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+
+			index := len(words)
+			if pool.size == 0 {
+				if index > 0 && options.accepts(words) {
+					found++
+					return yield(options.join(words))
+				}
+				return true
+			}
+
+			if !options.allowsMoreWords(index) {
+				return true
+			}
+
+			var previous string
+			if ordered && index > 0 {
+				previous = words[index-1]
+			}
+
+			key := deadEnd{pool: pool, index: index}
+			if bound, ok := deadEnds[key]; memoise && ok && previous >= bound {
+				return true
+			}
+			before := found
+
+			for length := 1; length <= min(pool.size, maxLength); length++ {
+				rest := pool.size - length
+				if !options.allowsWord(index, length) || (rest > 0 && !options.allowsMoreWords(index+1)) {
+					continue
+				}
+
+				if rest > 0 && !options.enumerated() && rest < options.MinWordLength {
+					continue
+				}
+
+				candidates := byLength[length]
+				if ordered && index > 0 {
+					start, _ := slices.BinarySearch(candidates, previous)
+					candidates = candidates[start:]
+				}
+
+				for _, candidate := range candidates {
+					next, ok := pool.without(candidate)
+					if !ok {
+						continue
+					}
+
+					words = append(words, candidate)
+					ok = combine(next)
+					words = words[:index]
+					if !ok {
+						return false
+					}
+				}
+			}
+
+			if memoise && found == before {
+				if bound, ok := deadEnds[key]; !ok || previous < bound {
+					deadEnds[key] = previous
+				}
+			}
+			return true
+		}
+
+		combine(pool)
+	}
+}
+
+// findSubAnagrams passes each valid word that can be made from some or all of the letters in the pool to yield, if
+// it is accepted. Words are built up one letter at a time, abandoning any that don't form valid prefixes,
+// and are found in lexicographical order. Letters are always taken from the pool before wildcards, so each word is
+// only produced once. The search stops if yield returns false or the context is cancelled, in which case false is
+// returned.
+func findSubAnagrams(ctx context.Context, checker Dictionary, pool letterPool, maxLength int, accept func(word string) bool, yield func(string) bool) bool {
+	var search func(current string) bool
+	search = func(current string) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if len(current) > 0 && accept(current) && checker.Valid(current) && !yield(current) {
+			return false
+		}
+
+		if len(current) >= maxLength {
+			return true
+		}
+
+		for c := byte('a'); c <= 'z'; c++ {
+			wildcard := pool.letters[c-'a'] == 0
+			if wildcard && pool.wildcards == 0 {
+				continue
+			}
+
+			next := current + string(c)
+			if !checker.Prefix(next) {
+				continue
+			}
+
+			if wildcard {
+				pool.wildcards--
+			} else {
+				pool.letters[c-'a']--
+			}
+
+			ok := search(next)
+
+			if wildcard {
+				pool.wildcards++
+			} else {
+				pool.letters[c-'a']++
+			}
+
+			if !ok {
+				return false
+			}
+		}
+		return true
 	}
 
-	input[k], input[l] = input[l], input[k]
-	return reverse(input, k+1)
+	return search("")
 }
@@ -2,7 +2,10 @@ package kowalski
 
 import (
 	"context"
+	"os"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -57,32 +60,204 @@ func TestMultiAnagrams(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := MultiAnagram(context.Background(), testChecker, tt.query); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := MultiAnagram(context.Background(), testChecker, tt.query, MultiWordOptions{}); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Anagram() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestPermute(t *testing.T) {
+func TestMultiAnagramSeq(t *testing.T) {
+	count := 0
+	for range MultiAnagramSeq(context.Background(), testChecker, "bfao?o", MultiWordOptions{}) {
+		count++
+		break
+	}
+
+	if count != 1 {
+		t.Errorf("MultiAnagramSeq() yielded %d results before stopping, want 1", count)
+	}
+}
+
+func TestMultiAnagramOptions(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("a\nact\ncat\ncats\nat\nsat\nscat\ntas\n"))
+
 	tests := []struct {
-		name       string
-		input      []byte
-		swapBefore int
-		want       []byte
+		name    string
+		query   string
+		options MultiWordOptions
+		want    []string
 	}{
-		{"Simple permutation 1", []byte("abc"), 3, []byte("acb")},
-		{"Simple permutation 2", []byte("acb"), 3, []byte("bac")},
-		{"Simple permutation 3", []byte("bac"), 3, []byte("bca")},
-		{"Simple permutation 4", []byte("bca"), 3, []byte("cab")},
-		{"Simple permutation 5", []byte("cab"), 3, []byte("cba")},
-		{"Simple permutation 6", []byte("cba"), 3, nil},
-		{"SwapBefore 1", []byte("abc"), 1, []byte("bac")},
+		{"default", "cats", MultiWordOptions{}, []string{"cats", "scat"}},
+		{"one letter words", "cata", MultiWordOptions{MinWordLength: 1}, []string{"a act", "a cat"}},
+		{"max word length", "scat", MultiWordOptions{MaxWordLength: 3}, nil},
+		{"exact word count", "actsat", MultiWordOptions{MinWords: 2, MaxWords: 2}, []string{"act sat", "act tas", "cat sat", "cat tas", "at scat", "at cats"}},
+		{"required word", "actsat", MultiWordOptions{Required: []string{"cat"}}, []string{"cat sat", "cat tas"}},
+		{"required word using wildcard", "?atsat", MultiWordOptions{Required: []string{"cat"}}, []string{"cat sat", "cat tas"}},
+		{"required word only", "tac", MultiWordOptions{Required: []string{"cat"}}, []string{"cat"}},
+		{"required word missing letters", "dog", MultiWordOptions{Required: []string{"cat"}}, nil},
+		{"enumeration", "actsat", MultiWordOptions{Enumeration: mustParseEnumeration("(2,4)")}, []string{"at cats", "at scat"}},
+		{"enumeration order", "actsat", MultiWordOptions{Enumeration: mustParseEnumeration("(4,2)")}, []string{"cats at", "scat at"}},
+		{"hyphenated enumeration", "actsat", MultiWordOptions{Enumeration: mustParseEnumeration("(3-3)")}, []string{"act-sat", "act-tas", "cat-sat", "cat-tas", "sat-act", "sat-cat", "tas-act", "tas-cat"}},
+		{"enumeration with required word", "actsat", MultiWordOptions{Required: []string{"cat"}, Enumeration: mustParseEnumeration("(3,3)")}, []string{"cat sat", "cat tas", "sat cat", "tas cat"}},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := permute(tt.input, tt.swapBefore); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("permute() = %v, want %v", string(got), string(tt.want))
+			got, _ := MultiAnagram(context.Background(), list, tt.query, tt.options)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("MultiAnagram() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMultiAnagramEnumerationMismatch(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("act\ncat\nsat\ntas\n"))
+
+	got, err := MultiAnagram(context.Background(), list, "actsat", MultiWordOptions{Enumeration: mustParseEnumeration("(3,4)")})
+	if err == nil || got != nil {
+		t.Errorf("MultiAnagram() = %v, %v, want an error for letters that don't fit the enumeration", got, err)
+	}
+}
+
+func TestSubAnagram(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("a\nact\ncat\ncats\nat\nsat\nscat\ntas\nzap\n"))
+
+	tests := []struct {
+		name    string
+		letters string
+		options SubAnagramOptions
+		want    []string
+	}{
+		{"default", "cats", SubAnagramOptions{}, []string{"act", "at", "cat", "cats", "sat", "scat", "tas"}},
+		{"min length", "cats", SubAnagramOptions{MinLength: 4}, []string{"cats", "scat"}},
+		{"single letters", "ca", SubAnagramOptions{MinLength: 1}, []string{"a"}},
+		{"required letter", "cats", SubAnagramOptions{Required: "c"}, []string{"act", "cat", "cats", "scat"}},
+		{"required repeated letter", "cats", SubAnagramOptions{Required: "cc"}, nil},
+		{"wildcard", "za?", SubAnagramOptions{}, []string{"at", "zap"}},
+		{"required letter from wildcard", "at?", SubAnagramOptions{Required: "s"}, []string{"sat", "tas"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := SubAnagram(context.Background(), list, tt.letters, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SubAnagram() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSubAnagramOptions(t *testing.T) {
+	input, options, err := ParseSubAnagramOptions("abc def min=4 using=X using=y")
+	if err != nil {
+		t.Fatalf("ParseSubAnagramOptions() error = %v", err)
+	}
+	if input != "abcdef" {
+		t.Errorf("ParseSubAnagramOptions() input = %q, want %q", input, "abcdef")
+	}
+	if want := (SubAnagramOptions{MinLength: 4, Required: "xy"}); options != want {
+		t.Errorf("ParseSubAnagramOptions() options = %+v, want %+v", options, want)
+	}
+
+	input, options, err = ParseSubAnagramOptions("abc with=ab def using=c")
+	if err != nil {
+		t.Fatalf("ParseSubAnagramOptions() error = %v", err)
+	}
+	if input != "abcdef with=ab" || options.Required != "c" {
+		t.Errorf("ParseSubAnagramOptions() = %q, %+v, want the required word left in the expression", input, options)
+	}
+
+	for _, invalid := range []string{"abc min=x", "abc using=?", "abc using=1", "abc foo=bar"} {
+		if _, _, err := ParseSubAnagramOptions(invalid); err == nil {
+			t.Errorf("ParseSubAnagramOptions(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestGroupByLength(t *testing.T) {
+	got := GroupByLength([]string{"cat", "a", "cats", "act", "at"})
+	want := []LengthGroup{
+		{4, []string{"cats"}},
+		{3, []string{"cat", "act"}},
+		{2, []string{"at"}},
+		{1, []string{"a"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByLength() = %v, want %v", got, want)
+	}
+}
+
+// loadBenchmarkChecker loads one of the shipped models to benchmark against realistic dictionaries.
+func loadBenchmarkChecker(b *testing.B) Dictionary {
+	b.Helper()
+
+	f, err := os.Open("models/enable.wl")
+	if err != nil {
+		b.Skipf("Unable to open model: %v", err)
+	}
+	defer f.Close()
+
+	checker, err := LoadModel(f)
+	if err != nil {
+		b.Fatalf("Unable to load model: %v", err)
+	}
+	return checker
+}
+
+func BenchmarkAnagram(b *testing.B) {
+	checker := loadBenchmarkChecker(b)
+
+	benchmarks := []struct {
+		name  string
+		input string
+	}{
+		{"15 letters", "tnhgeeirtnsnsig"},
+		{"17 letters", "noorcetlnuvieruot"},
+		{"18 letters with wildcards", "interrelationsh??s"},
+		{"19 letters with wildcards", "internationaliza??n"},
+		{"20 letters", "ahcuanetsciyltrclaic"},
+		{"15 wildcards", "???????????????"},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := Anagram(context.Background(), checker, bm.input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMultiAnagram(b *testing.B) {
+	checker := loadBenchmarkChecker(b)
+
+	benchmarks := []struct {
+		name    string
+		input   string
+		options MultiWordOptions
+	}{
+		{"15 letters with wildcards, two words", "listenquietly??", MultiWordOptions{MinWords: 2, MaxWords: 2, MinWordLength: 4}},
+		{"16 letters with wildcards, two words", "crosswordclues??", MultiWordOptions{MinWords: 2, MaxWords: 2, MinWordLength: 4}},
+		{"16 letters, up to three words", "kowalskianalysis", MultiWordOptions{MinWordLength: 3, MaxWords: 3}},
+		{"17 letters with wildcards, enumeration", "mastermindpuzzle?", MultiWordOptions{Enumeration: mustParseEnumeration("(10,7)")}},
+		{"20 letters, first 100 results", "thequickbrownfoxjump", MultiWordOptions{MinWordLength: 3}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				count := 0
+				for range MultiAnagramSeq(context.Background(), checker, bm.input, bm.options) {
+					count++
+					if count == 100 {
+						break
+					}
+				}
 			}
 		})
 	}
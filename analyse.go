@@ -1,47 +1,94 @@
 package kowalski
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/big"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/csmith/cryptography"
+	"github.com/csmith/kowalski/v6/cipher"
 	"github.com/csmith/kowalski/v6/data"
 )
 
-var nonLetterRegex = regexp.MustCompile("[^a-z]+")
+var (
+	nonLetterRegex      = regexp.MustCompile("[^a-z]+")
+	morseSeparatorRegex = regexp.MustCompile(`[\s/|]+`)
+)
+
+// AnalysisCategory groups related findings from Analyse.
+type AnalysisCategory string
+
+const (
+	// StatisticsAnalysis findings describe properties of the input, such as its length or entropy.
+	StatisticsAnalysis AnalysisCategory = "statistics"
+	// PatternAnalysis findings describe structure within the input, such as palindromes or hidden messages.
+	PatternAnalysis AnalysisCategory = "pattern"
+	// EncodingAnalysis findings suggest the input is encoded, such as with morse code or run-length encoding.
+	EncodingAnalysis AnalysisCategory = "encoding"
+	// CipherAnalysis findings suggest the input is encrypted, and may include a decryption.
+	CipherAnalysis AnalysisCategory = "cipher"
+)
+
+// Analysis is a single finding from Analyse.
+type Analysis struct {
+	// Analyser identifies the analyser that made the finding, such as "caesar".
+	Analyser string `json:"analyser"`
+	// Category groups related findings.
+	Category AnalysisCategory `json:"category"`
+	// Message describes the finding, such as "Caesar shift of 3 might be English: ...".
+	Message string `json:"message"`
+	// Confidence indicates how likely the finding is to be meaningful, from 0 to 1. Facts about the input have a
+	// confidence of 1; findings about decoded text use its score.
+	Confidence float64 `json:"confidence"`
+	// Output is the text produced by decoding the input, if the finding involves decoding it.
+	Output string `json:"output,omitempty"`
+	// Command is a suggested command for the Discord bot or web UI to investigate further, such as
+	// "crack vigenere ...". The command name is followed by its input.
+	Command string `json:"command,omitempty"`
+}
 
-type analyser func(checker *SpellChecker, input string) []string
+// String returns the finding's message.
+func (a Analysis) String() string {
+	return a.Message
+}
 
-func analyseEntropy(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseEntropy(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	entropy := cryptography.ShannonEntropy([]byte(input))
 	if entropy <= 0.5 {
-		results = append(results, fmt.Sprintf("Shannon entropy is %.2f - very little variation in input", entropy))
+		results = append(results, Analysis{Message: fmt.Sprintf("Shannon entropy is %.2f - very little variation in input", entropy), Confidence: 1})
 	} else if entropy >= 3.5 && entropy <= 5 {
-		results = append(results, fmt.Sprintf("Shannon entropy is %.2f - typical of English text", entropy))
+		results = append(results, Analysis{Message: fmt.Sprintf("Shannon entropy is %.2f - typical of English text", entropy), Confidence: 1})
 	} else if entropy >= 7.5 {
-		results = append(results, fmt.Sprintf("Shannon entropy is %.2f - very high, likely encrypted/compressed", entropy))
+		results = append(results, Analysis{Message: fmt.Sprintf("Shannon entropy is %.2f - very high, likely encrypted/compressed", entropy), Confidence: 1})
 	}
 
 	return results
 }
 
-func analyseDataReferences(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseDataReferences(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	cleaned := nonLetterRegex.ReplaceAllString(strings.ToLower(input), "")
 	if len(cleaned) > 0 {
 		for name := range data.Index {
 			if terms, ok := splitTerms(cleaned, nil, data.Index[name]); ok {
 				if sameLength(data.Index[name]) {
-					results = append(results, fmt.Sprintf("Consists entirely of %s", name))
+					results = append(results, Analysis{Message: fmt.Sprintf("Consists entirely of %s", name), Confidence: 1})
 				} else {
-					results = append(results, fmt.Sprintf("Consists entirely of %s: %s", name, strings.Join(terms, " ")))
+					results = append(results, Analysis{
+						Message:    fmt.Sprintf("Consists entirely of %s: %s", name, strings.Join(terms, " ")),
+						Confidence: 1,
+						Output:     strings.Join(terms, " "),
+					})
 				}
 			}
 		}
@@ -50,14 +97,14 @@ func analyseDataReferences(_ *SpellChecker, input string) []string {
 	return results
 }
 
-func analyseCaesarShifts(checker *SpellChecker, input string) []string {
-	var results []string
+func analyseCaesarShifts(_ context.Context, _ Dictionary, scorer Scorer, input string) []Analysis {
+	var results []Analysis
 
 	shifts := cryptography.CaesarShifts([]byte(input))
 	bestScore, bestShift := 0.0, 0
 	for i, s := range shifts {
 		if i > 0 {
-			score := Score(checker, string(s))
+			score := scorer.Score(string(s))
 			if score > bestScore {
 				bestScore = score
 				bestShift = i
@@ -65,14 +112,19 @@ func analyseCaesarShifts(checker *SpellChecker, input string) []string {
 		}
 	}
 	if bestScore > 0.5 {
-		results = append(results, fmt.Sprintf("Caesar shift of %d might be English: %s (%.5f)", bestShift, shifts[bestShift], bestScore))
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Caesar shift of %d might be English: %s (%.5f)", bestShift, shifts[bestShift], bestScore),
+			Confidence: bestScore,
+			Output:     string(shifts[bestShift]),
+			Command:    fmt.Sprintf("shift %s", input),
+		})
 	}
 
 	return results
 }
 
-func analyseAlternateChars(checker *SpellChecker, input string) []string {
-	var results []string
+func analyseAlternateChars(_ context.Context, _ Dictionary, scorer Scorer, input string) []Analysis {
+	var results []Analysis
 
 	odds := strings.Builder{}
 	evens := strings.Builder{}
@@ -84,37 +136,39 @@ func analyseAlternateChars(checker *SpellChecker, input string) []string {
 		}
 	}
 
-	if score := Score(checker, odds.String()); score > 0.5 {
-		results = append(results, fmt.Sprintf("Alternating characters might be English: %s (%.5f)", odds.String(), score))
-	}
-
-	if score := Score(checker, evens.String()); score > 0.5 {
-		results = append(results, fmt.Sprintf("Alternating characters might be English: %s (%.5f)", evens.String(), score))
+	for _, output := range []string{odds.String(), evens.String()} {
+		if score := scorer.Score(output); score > 0.5 {
+			results = append(results, Analysis{
+				Message:    fmt.Sprintf("Alternating characters might be English: %s (%.5f)", output, score),
+				Confidence: score,
+				Output:     output,
+			})
+		}
 	}
 
 	return results
 }
 
-func analyseLength(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseLength(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	cleaned := nonLetterRegex.ReplaceAllString(strings.ToLower(input), "")
 	if len(input)%8 == 0 {
-		results = append(results, "Multiple of 8 characters - might be encoded binary?")
+		results = append(results, Analysis{Category: EncodingAnalysis, Message: "Multiple of 8 characters - might be encoded binary?", Confidence: 0.25})
 	} else if len(cleaned)%8 == 0 {
-		results = append(results, "Multiple of 8 A-Z characters - might be encoded binary?")
+		results = append(results, Analysis{Category: EncodingAnalysis, Message: "Multiple of 8 A-Z characters - might be encoded binary?", Confidence: 0.25})
 	}
 
-	results = append(results, fmt.Sprintf("%d characters long (total)", len(input)))
-	results = append(results, fmt.Sprintf("%d characters long (a-zA-Z)", len(cleaned)))
+	results = append(results, Analysis{Message: fmt.Sprintf("%d characters long (total)", len(input)), Confidence: 1})
+	results = append(results, Analysis{Message: fmt.Sprintf("%d characters long (a-zA-Z)", len(cleaned)), Confidence: 1})
 
 	return results
 }
 
-func analyseDistribution(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseDistribution(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
-	dists := cryptography.LetterDistribution([]byte(input))
+	dists := cryptography.LetterDistribution([]byte(strings.ToLower(input)))
 	present := 0
 	for i := range dists {
 		if dists[i] > 0 {
@@ -133,7 +187,7 @@ func analyseDistribution(_ *SpellChecker, input string) []string {
 				}
 			}
 		}
-		results = append(results, message.String())
+		results = append(results, Analysis{Message: message.String(), Confidence: 1})
 	}
 
 	if present > 0 && present < 10 && present < len(input) {
@@ -144,11 +198,11 @@ func analyseDistribution(_ *SpellChecker, input string) []string {
 			}
 		}
 
-		results = append(results, fmt.Sprintf("Contains only some letters: %s", chars.String()))
+		results = append(results, Analysis{Message: fmt.Sprintf("Contains only some letters: %s", chars.String()), Confidence: 1})
 		if chars.String() == "ADFGX" {
-			results = append(results, "Might be an ADFGX cipher?")
+			results = append(results, Analysis{Category: CipherAnalysis, Message: "Might be an ADFGX cipher?", Confidence: 0.75})
 		} else if chars.String() == "ADFGVX" {
-			results = append(results, "Might be an ADFGVX cipher?")
+			results = append(results, Analysis{Category: CipherAnalysis, Message: "Might be an ADFGVX cipher?", Confidence: 0.75})
 		}
 	}
 
@@ -157,44 +211,44 @@ func analyseDistribution(_ *SpellChecker, input string) []string {
 
 var rleRegex = regexp.MustCompile(`^(\d+\D)+$`)
 
-func analyseRunLengthEncoding(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseRunLengthEncoding(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	if rleRegex.MatchString(input) {
-		message := strings.Builder{}
-		message.WriteString("Might be run-length encoded: ")
+		output := strings.Builder{}
 		num := 0
 		for i := range input {
 			if d, err := strconv.Atoi(string(input[i])); err == nil {
 				num = 10*num + d
 			} else {
-				message.WriteString(strings.Repeat(string(input[i]), num))
+				output.WriteString(strings.Repeat(string(input[i]), num))
 				num = 0
 			}
 		}
-		if message.Len() > 250 {
-			results = append(results, fmt.Sprintf("%s...", message.String()[0:247]))
-		} else {
-			results = append(results, message.String())
+
+		message := fmt.Sprintf("Might be run-length encoded: %s", output.String())
+		if len(message) > 250 {
+			message = fmt.Sprintf("%s...", message[0:247])
 		}
+		results = append(results, Analysis{Message: message, Confidence: 0.5, Output: output.String()})
 	}
 
 	return results
 }
 
-func analyseWordCount(_ *SpellChecker, input string) []string {
-	var results []string
+func analyseWordCount(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	if strings.Contains(input, " ") {
 		words := strings.Fields(input)
-		results = append(results, fmt.Sprintf("%d words long", len(words)))
+		results = append(results, Analysis{Message: fmt.Sprintf("%d words long", len(words)), Confidence: 1})
 	}
 
 	return results
 }
 
-func analysePalindromes(_ *SpellChecker, input string) []string {
-	var results []string
+func analysePalindromes(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
 	words := strings.Fields(input)
 	var singleWordPalindromes []string
@@ -225,11 +279,17 @@ func analysePalindromes(_ *SpellChecker, input string) []string {
 	}
 
 	if len(singleWordPalindromes) > 0 {
-		results = append(results, fmt.Sprintf("Contains %d single-word palindromes: %s", len(singleWordPalindromes), strings.Join(singleWordPalindromes, ", ")))
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Contains %d single-word palindromes: %s", len(singleWordPalindromes), strings.Join(singleWordPalindromes, ", ")),
+			Confidence: 1,
+		})
 	}
 
 	if len(multiWordPalindromes) > 0 {
-		results = append(results, fmt.Sprintf("Contains %d multi-word palindromes: %s", len(multiWordPalindromes), strings.Join(multiWordPalindromes, ", ")))
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Contains %d multi-word palindromes: %s", len(multiWordPalindromes), strings.Join(multiWordPalindromes, ", ")),
+			Confidence: 1,
+		})
 	}
 
 	return results
@@ -244,8 +304,8 @@ func isPalindrome(s string) bool {
 	return true
 }
 
-func analysePrimes(checker *SpellChecker, input string) []string {
-	var results []string
+func analysePrimes(_ context.Context, _ Dictionary, scorer Scorer, input string) []Analysis {
+	var results []Analysis
 
 	output := strings.Builder{}
 	for i := range input {
@@ -254,14 +314,18 @@ func analysePrimes(checker *SpellChecker, input string) []string {
 		}
 	}
 
-	if score := Score(checker, output.String()); score > 0.5 {
-		results = append(results, fmt.Sprintf("Prime characters might be English: %s (%.5f)", output.String(), score))
+	if score := scorer.Score(output.String()); score > 0.5 {
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Prime characters might be English: %s (%.5f)", output.String(), score),
+			Confidence: score,
+			Output:     output.String(),
+		})
 	}
 
 	return results
 }
 
-func analyseCommonLetters(_ *SpellChecker, input string) []string {
+func analyseCommonLetters(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
 	words := strings.Fields(strings.ToLower(input))
 
 	var matches [26]int
@@ -282,40 +346,378 @@ func analyseCommonLetters(_ *SpellChecker, input string) []string {
 	}
 
 	if len(common) > 0 {
-		return []string{fmt.Sprintf("All words contain the letters: %s", common)}
+		return []Analysis{{Message: fmt.Sprintf("All words contain the letters: %s", common), Confidence: 1}}
 	} else {
 		return nil
 	}
 }
 
-var analysers = []analyser{
-	analyseEntropy,
-	analyseDataReferences,
-	analyseCaesarShifts,
-	analyseAlternateChars,
-	analysePrimes,
-	analyseCommonLetters,
-	analyseLength,
-	analyseDistribution,
-	analyseRunLengthEncoding,
-	analyseWordCount,
-	analysePalindromes,
+// maxDecodeAnalysisChains is the number of decoding chains reported by analyseEncodings.
+const maxDecodeAnalysisChains = 3
+
+// analyseEncodings looks for layers of encoding using AutoDecode. Single decodings that other analysers already report
+// (Caesar shifts, morse code and run-length encoding) are omitted.
+func analyseEncodings(ctx context.Context, checker Dictionary, scorer Scorer, input string) []Analysis {
+	var results []Analysis
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	chains, _ := AutoDecode(ctx, checker, input, AutoDecodeOptions{Scorer: scorer, MaxDepth: 3, Results: 10})
+	for _, chain := range chains {
+		if chain.Score() <= 0.5 || len(results) == maxDecodeAnalysisChains {
+			break
+		}
+
+		if decoder := chain.Steps[0].Decoder; len(chain.Steps) == 1 && (strings.HasPrefix(decoder, "rot") || strings.HasPrefix(decoder, "morse") || decoder == "rle") {
+			continue
+		}
+
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Might be encoded as %s (%.5f)", chain, chain.Score()),
+			Confidence: chain.Score(),
+			Output:     chain.Output(),
+			Command:    fmt.Sprintf("decode %s", input),
+		})
+	}
+
+	return results
 }
 
-// Analyse performs various forms of text analysis on the input and returns findings.
-func Analyse(checker *SpellChecker, input string) []string {
-	var results []string
+// maxMorseAnalysisWords is the number of words reported for each mapping of unspaced morse input.
+const maxMorseAnalysisWords = 5
+
+func analyseMorse(ctx context.Context, checker Dictionary, _ Scorer, input string) []Analysis {
+	var results []Analysis
 
-	for i := range analysers {
-		results = append(results, analysers[i](checker, input)...)
+	symbols := morseSymbolsIn(input)
+	if len(symbols) == 0 || len(symbols) > 3 || len(morseSeparatorRegex.ReplaceAllString(input, "")) < 4 {
+		return nil
+	}
+
+	// Words like "noon" only use a few different letters, but are far more likely to just be words.
+	if words := strings.Fields(strings.ToLower(input)); !slices.ContainsFunc(words, func(word string) bool {
+		return !checker.Valid(word)
+	}) {
+		return nil
+	}
+
+	if isStandardMorse(symbols) {
+		results = append(results, Analysis{
+			Message:    "Consists only of dots and dashes - might be morse code",
+			Confidence: 0.75,
+			Command:    fmt.Sprintf("morse %s", input),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	for _, mapping := range MorseMappings(input) {
+		if parseMorse(mapping.Signals).spaced {
+			decoded, err := DecodeMorse(mapping.Signals)
+			if err != nil {
+				continue
+			}
+
+			words := strings.Fields(decoded)
+			valid := 0
+			for _, word := range words {
+				if checker.Valid(word) {
+					valid++
+				}
+			}
+
+			if valid > 0 && valid*2 >= len(words) {
+				results = append(results, Analysis{
+					Message:    fmt.Sprintf("Might be morse code (%s): %s", mapping.Description, decoded),
+					Confidence: float64(valid) / float64(len(words)),
+					Output:     decoded,
+					Command:    fmt.Sprintf("morse %s", input),
+				})
+			}
+			continue
+		}
+
+		var words []string
+		for word := range FromMorseSeq(ctx, checker, mapping.Signals) {
+			words = append(words, word)
+			if len(words) == maxMorseAnalysisWords {
+				break
+			}
+		}
+
+		if len(words) > 0 {
+			results = append(results, Analysis{
+				Message:    fmt.Sprintf("Might be morse code (%s): %s", mapping.Description, strings.Join(words, ", ")),
+				Confidence: 0.5,
+				Output:     words[0],
+				Command:    fmt.Sprintf("morse %s", input),
+			})
+		}
 	}
 
 	return results
 }
 
+const (
+	// minCrackLetters is the number of letters needed before analyseCipherStatistics draws conclusions.
+	minCrackLetters = 40
+	// minCrackScore is the score a cracked plaintext needs before analyseCipherStatistics reports it. This is
+	// higher than for other analysers, as crackers choose keys that make their output look as English as possible.
+	minCrackScore = 0.85
+	// minCrackLetterProportion is the proportion of the input's non-space characters that must be letters before
+	// analyseCipherStatistics looks for a cipher, so that encodings such as base64 and hex aren't mistaken for one.
+	minCrackLetterProportion = 0.9
+	// minCrackCoverage is the proportion of a cracked plaintext's letters that must be part of dictionary words before
+	// analyseCipherStatistics reports it. When the scorer is also used to guide the cracker it will rate the output
+	// highly whether or not it makes sense, so the dictionary provides an independent check.
+	minCrackCoverage = 0.6
+)
+
+func analyseCipherStatistics(ctx context.Context, checker Dictionary, scorer Scorer, input string) []Analysis {
+	var results []Analysis
+
+	cleaned := nonLetterRegex.ReplaceAllString(strings.ToLower(input), "")
+	characters := len(strings.Join(strings.Fields(input), ""))
+	if len(cleaned) < minCrackLetters || float64(len(cleaned)) < float64(characters)*minCrackLetterProportion {
+		return nil
+	}
+
+	// plausible determines whether a cracked plaintext is worth reporting, returning its score.
+	plausible := func(plaintext string) (float64, bool) {
+		score := scorer.Score(plaintext)
+		return score, score > minCrackScore && wordCoverage(checker, plaintext) >= minCrackCoverage
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	// Scorers that can rank texts beyond the range of Score (such as NGrams) are fast and precise enough to guide the
+	// crackers; others are only used to decide whether the result is worth reporting.
+	options := cipher.CrackOptions{Results: 3}
+	if f, ok := scorer.(interface{ Fitness(string) float64 }); ok {
+		options.Fitness = f.Fitness
+	}
+
+	ioc := cryptography.IndexOfCoincidence([]byte(cleaned))
+	if ioc < cryptography.IndexOfCoincidenceEnglish*0.8 {
+		results = append(results, Analysis{
+			Message:    fmt.Sprintf("Index of coincidence is %.2f - might be a polyalphabetic cipher such as Vigenère", ioc),
+			Confidence: 0.5,
+			Command:    fmt.Sprintf("crack vigenere %s", input),
+		})
+		if candidates, err := cipher.CrackVigenere(ctx, input, options); err == nil && len(candidates) > 0 {
+			if score, ok := plausible(candidates[0].Plaintext); ok {
+				results = append(results, Analysis{
+					Message:    fmt.Sprintf("Vigenère key %s might give: %s (%.5f)", candidates[0].Key, candidates[0].Plaintext, score),
+					Confidence: score,
+					Output:     candidates[0].Plaintext,
+					Command:    fmt.Sprintf("decrypt vigenere key=%s %s", candidates[0].Key, input),
+				})
+			}
+		}
+	} else if cipher.BigramFitness(input) < -2.2 {
+		// English text typically has a bigram fitness of around -1.5, and random letters around -2.5
+		results = append(results, Analysis{
+			Message:    "Letter frequencies are typical of English but bigrams aren't - might be a substitution cipher",
+			Confidence: 0.5,
+			Command:    fmt.Sprintf("crack substitution %s", input),
+		})
+		options.Restarts = 10
+		if candidates, err := cipher.CrackSubstitution(ctx, input, options); err == nil && len(candidates) > 0 {
+			if score, ok := plausible(candidates[0].Plaintext); ok {
+				results = append(results, Analysis{
+					Message:    fmt.Sprintf("Substitution key %s might give: %s (%.5f)", candidates[0].Key, candidates[0].Plaintext, score),
+					Confidence: score,
+					Output:     candidates[0].Plaintext,
+					Command:    fmt.Sprintf("decrypt substitution key=%s %s", candidates[0].Key, input),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// wordCoverage returns the proportion of the letters in the text that are part of dictionary words. Runs of letters
+// that are words in their entirety count in full, regardless of length; within other runs only words of at least four
+// letters count, as shorter words turn up by chance even in random letters.
+func wordCoverage(checker Dictionary, text string) float64 {
+	covered, total := 0, 0
+	for _, run := range nonLetterRegex.Split(strings.ToLower(text), -1) {
+		total += len(run)
+		if run == "" {
+			continue
+		}
+		if checker.Valid(run) {
+			covered += len(run)
+			continue
+		}
+
+		letters := make([]bool, len(run))
+		findWords(checker, run, func(start, end int) bool {
+			if end-start >= 4 {
+				for i := start; i < end; i++ {
+					letters[i] = true
+				}
+			}
+			return true
+		})
+		for i := range letters {
+			if letters[i] {
+				covered++
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(covered) / float64(total)
+}
+
+var (
+	// analysers contains every registered analyser, in the order their findings are reported.
+	analysers = []Analyser{
+		NewAnalyser("entropy", StatisticsAnalysis, analyseEntropy),
+		NewAnalyser("data", PatternAnalysis, analyseDataReferences),
+		NewAnalyser("caesar", CipherAnalysis, analyseCaesarShifts),
+		NewAnalyser("alternate", PatternAnalysis, analyseAlternateChars),
+		NewAnalyser("primes", PatternAnalysis, analysePrimes),
+		NewAnalyser("common", PatternAnalysis, analyseCommonLetters),
+		NewAnalyser("length", StatisticsAnalysis, analyseLength),
+		NewAnalyser("distribution", StatisticsAnalysis, analyseDistribution),
+		NewAnalyser("ciphers", CipherAnalysis, analyseCipherStatistics, "slow"),
+		NewAnalyser("rle", EncodingAnalysis, analyseRunLengthEncoding),
+		NewAnalyser("morse", EncodingAnalysis, analyseMorse, "slow"),
+		NewAnalyser("decode", EncodingAnalysis, analyseEncodings, "slow"),
+		NewAnalyser("words", StatisticsAnalysis, analyseWordCount),
+		NewAnalyser("palindromes", PatternAnalysis, analysePalindromes),
+	}
+	analysersLock sync.RWMutex
+)
+
+// AnalysisOption configures how Analyse examines its input.
+type AnalysisOption func(*analysisOptions)
+
+type analysisOptions struct {
+	scorer Scorer
+	only   []string
+	skip   []string
+}
+
+// WithScorer sets the Scorer used to judge whether transformations of the input (such as Caesar shifts) produce
+// English text. By default, a DictionaryScorer using the checker passed to Analyse is used; an NGrams model is
+// considerably more accurate.
+func WithScorer(scorer Scorer) AnalysisOption {
+	return func(options *analysisOptions) {
+		options.scorer = scorer
+	}
+}
+
+// WithAnalysers limits analysis to the analysers with the given names or tags. If given more than once, analysers
+// matching any of the selectors are run.
+func WithAnalysers(selectors ...string) AnalysisOption {
+	return func(options *analysisOptions) {
+		options.only = append(options.only, selectors...)
+	}
+}
+
+// WithoutAnalysers prevents the analysers with the given names or tags from running, even if they were selected with
+// WithAnalysers.
+func WithoutAnalysers(selectors ...string) AnalysisOption {
+	return func(options *analysisOptions) {
+		options.skip = append(options.skip, selectors...)
+	}
+}
+
+// Analyse performs various forms of text analysis on the input and returns findings. The registered analysers (or
+// those selected by the options) run concurrently, and their findings are returned in the order the analysers were
+// registered. If the context is done before every analyser has finished, the findings of those that did finish are
+// returned along with the context's error. An error is also returned if the options name an unknown analyser or tag.
+func Analyse(ctx context.Context, checker Dictionary, input string, opts ...AnalysisOption) ([]Analysis, error) {
+	o := &analysisOptions{scorer: DictionaryScorer(checker)}
+	for i := range opts {
+		opts[i](o)
+	}
+
+	selected, err := selectAnalysers(Analysers(), o.only, o.skip)
+	if err != nil {
+		return nil, err
+	}
+
+	type findings struct {
+		index   int
+		results []Analysis
+	}
+
+	// Buffered so that analysers that overrun the deadline can still finish without blocking
+	done := make(chan findings, len(selected))
+	for i := range selected {
+		go func() {
+			done <- findings{index: i, results: selected[i].Analyse(ctx, checker, o.scorer, input)}
+		}()
+	}
+
+	byAnalyser := make([][]Analysis, len(selected))
+	for remaining := len(selected); remaining > 0; remaining-- {
+		select {
+		case f := <-done:
+			byAnalyser[f.index] = f.results
+		case <-ctx.Done():
+			// Include any analysers that finished at the same time as the deadline
+			for len(done) > 0 {
+				f := <-done
+				byAnalyser[f.index] = f.results
+				remaining--
+			}
+			if remaining > 0 {
+				return flattenFindings(selected, byAnalyser), ctx.Err()
+			}
+			return flattenFindings(selected, byAnalyser), nil
+		}
+	}
+
+	return flattenFindings(selected, byAnalyser), nil
+}
+
+// flattenFindings combines the findings of each analyser into a single slice, recording which analyser made each.
+func flattenFindings(selected []Analyser, byAnalyser [][]Analysis) []Analysis {
+	var results []Analysis
+	for i := range byAnalyser {
+		for _, result := range byAnalyser[i] {
+			result.Analyser = selected[i].Name()
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Scorer rates how likely a text is to be English. A score of 1.0 means almost certainly English, a score of 0.0
+// means almost certainly not.
+type Scorer interface {
+	Score(input string) float64
+}
+
+// DictionaryScorer returns a Scorer that uses the Score function with the given dictionary. Text is lowercased before
+// it is scored, as dictionaries only contain lowercase words.
+func DictionaryScorer(checker Dictionary) Scorer {
+	return dictionaryScorer{checker: checker}
+}
+
+type dictionaryScorer struct {
+	checker Dictionary
+}
+
+func (d dictionaryScorer) Score(input string) float64 {
+	return Score(d.checker, strings.ToLower(input))
+}
+
 // Score assigns a score to an input showing how likely it is to be English text. A score of 1.0 means almost
-// certainly English, a score of 0.0 means almost certainly not. This is fairly arbitrary and is not very good.
-func Score(checker *SpellChecker, input string) float64 {
+// certainly English, a score of 0.0 means almost certainly not. This is fairly arbitrary and is not very good; an
+// NGrams model trained on a corpus of English text gives much better results.
+func Score(checker Dictionary, input string) float64 {
 	density := scoreWord(checker, input)
 	entropy := scoreEntropy(input)
 	bigram := scoreBigrams(input)
@@ -325,12 +727,13 @@ func Score(checker *SpellChecker, input string) float64 {
 }
 
 // scoreWord returns a score for the text based on how many english words occur within it.
-func scoreWord(checker *SpellChecker, input string) float64 {
+func scoreWord(checker Dictionary, input string) float64 {
 	words := make([]int, len(input))
-	findWords(checker, input, func(start, end int) {
+	findWords(checker, input, func(start, end int) bool {
 		for i := start; i < end; i++ {
 			words[i]++
 		}
+		return true
 	})
 
 	mean := float64(0)
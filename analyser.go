@@ -0,0 +1,159 @@
+package kowalski
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Analyser examines text for Analyse and reports anything interesting about it. The built-in analysers are
+// registered automatically; others can be added with RegisterAnalyser.
+type Analyser interface {
+	// Name uniquely identifies the analyser, such as "caesar". It is recorded in each of the analyser's findings,
+	// and can be used to select or skip the analyser.
+	Name() string
+	// Tags returns additional names the analyser can be selected or skipped by, such as its category or "slow".
+	Tags() []string
+	// Analyse examines the input and returns any findings. The checker can be used to look up words, and the scorer
+	// to judge whether transformations of the input are English. Analysers should return promptly once the context
+	// is done.
+	Analyse(ctx context.Context, checker Dictionary, scorer Scorer, input string) []Analysis
+}
+
+// AnalyserFunc examines the input on behalf of an analyser created with NewAnalyser.
+type AnalyserFunc func(ctx context.Context, checker Dictionary, scorer Scorer, input string) []Analysis
+
+// NewAnalyser creates an Analyser that runs the given function. The analyser is tagged with its category and any
+// additional tags, and findings that don't specify a category are given the analyser's.
+func NewAnalyser(name string, category AnalysisCategory, analyse AnalyserFunc, tags ...string) Analyser {
+	return &funcAnalyser{
+		name:     name,
+		category: category,
+		tags:     append([]string{string(category)}, tags...),
+		analyse:  analyse,
+	}
+}
+
+type funcAnalyser struct {
+	name     string
+	category AnalysisCategory
+	tags     []string
+	analyse  AnalyserFunc
+}
+
+func (a *funcAnalyser) Name() string {
+	return a.name
+}
+
+func (a *funcAnalyser) Tags() []string {
+	return a.tags
+}
+
+func (a *funcAnalyser) Analyse(ctx context.Context, checker Dictionary, scorer Scorer, input string) []Analysis {
+	results := a.analyse(ctx, checker, scorer, input)
+	for i := range results {
+		if results[i].Category == "" {
+			results[i].Category = a.category
+		}
+	}
+	return results
+}
+
+// RegisterAnalyser adds an analyser to those run by Analyse. Its findings are reported after those of the analysers
+// already registered. An error is returned if the analyser's name is already in use, or isn't a single word.
+func RegisterAnalyser(analyser Analyser) error {
+	name := analyser.Name()
+	if name == "" || strings.ContainsAny(name, ", \t\n") {
+		return fmt.Errorf("invalid analyser name %q", name)
+	}
+
+	analysersLock.Lock()
+	defer analysersLock.Unlock()
+
+	for i := range analysers {
+		if strings.EqualFold(analysers[i].Name(), name) {
+			return fmt.Errorf("analyser %s is already registered", name)
+		}
+	}
+
+	analysers = append(analysers, analyser)
+	return nil
+}
+
+// Analysers returns the registered analysers, in the order their findings are reported.
+func Analysers() []Analyser {
+	analysersLock.RLock()
+	defer analysersLock.RUnlock()
+
+	return slices.Clone(analysers)
+}
+
+// selectAnalysers returns the analysers matching any of the selectors in only (or all of them, if only is empty),
+// excluding any that match a selector in skip. An error is returned if a selector doesn't match any analyser.
+func selectAnalysers(all []Analyser, only, skip []string) ([]Analyser, error) {
+	for _, selector := range slices.Concat(only, skip) {
+		if !slices.ContainsFunc(all, func(a Analyser) bool { return analyserMatches(a, selector) }) {
+			return nil, fmt.Errorf("unknown analyser or tag: %s", selector)
+		}
+	}
+
+	var selected []Analyser
+	for _, a := range all {
+		if len(only) > 0 && !slices.ContainsFunc(only, func(s string) bool { return analyserMatches(a, s) }) {
+			continue
+		}
+		if slices.ContainsFunc(skip, func(s string) bool { return analyserMatches(a, s) }) {
+			continue
+		}
+		selected = append(selected, a)
+	}
+	return selected, nil
+}
+
+// analyserMatches determines whether the selector is the analyser's name or one of its tags.
+func analyserMatches(a Analyser, selector string) bool {
+	if strings.EqualFold(a.Name(), selector) {
+		return true
+	}
+	return slices.ContainsFunc(a.Tags(), func(tag string) bool { return strings.EqualFold(tag, selector) })
+}
+
+// ParseAnalysisOptions extracts analyser selections from the start of the input, returning the remaining text
+// unchanged and the corresponding options. Only leading fields are examined, as the text being analysed may itself
+// contain '='. Supported fields are:
+//
+//   - only: a comma-separated list of analyser names or tags to run (see WithAnalysers)
+//   - skip: a comma-separated list of analyser names or tags not to run (see WithoutAnalysers)
+//
+// For example, "skip=slow,palindromes uryyb" returns "uryyb" and an option that skips the slow analysers and the
+// palindrome analyser. Selectors are checked when the options are passed to Analyse.
+func ParseAnalysisOptions(input string) (string, []AnalysisOption, error) {
+	var options []AnalysisOption
+
+	for {
+		trimmed := strings.TrimLeft(input, " ")
+		field, rest, _ := strings.Cut(trimmed, " ")
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return input, options, nil
+		}
+
+		var option func(...string) AnalysisOption
+		switch strings.ToLower(key) {
+		case "only":
+			option = WithAnalysers
+		case "skip":
+			option = WithoutAnalysers
+		default:
+			return input, options, nil
+		}
+
+		selectors := slices.DeleteFunc(strings.Split(value, ","), func(s string) bool { return s == "" })
+		if len(selectors) == 0 {
+			return "", nil, fmt.Errorf("option %s requires analyser names or tags", key)
+		}
+		options = append(options, option(selectors...))
+		input = rest
+	}
+}
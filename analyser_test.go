@@ -0,0 +1,187 @@
+package kowalski
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withAnalysers replaces the registered analysers for the duration of a test.
+func withAnalysers(t *testing.T, extra ...Analyser) {
+	saved := Analysers()
+	t.Cleanup(func() {
+		analysersLock.Lock()
+		defer analysersLock.Unlock()
+		analysers = saved
+	})
+
+	for _, analyser := range extra {
+		if err := RegisterAnalyser(analyser); err != nil {
+			t.Fatalf("RegisterAnalyser() error = %v", err)
+		}
+	}
+}
+
+func analyserNames(results []Analysis) []string {
+	var names []string
+	for _, result := range results {
+		if !slices.Contains(names, result.Analyser) {
+			names = append(names, result.Analyser)
+		}
+	}
+	return names
+}
+
+func TestAnalyse_Selection(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []AnalysisOption
+		want []string
+	}{
+		{"by name", []AnalysisOption{WithAnalysers("words", "length")}, []string{"length", "words"}},
+		{"by tag", []AnalysisOption{WithAnalysers("statistics")}, []string{"entropy", "length", "words"}},
+		{"skipped", []AnalysisOption{WithAnalysers("statistics"), WithoutAnalysers("entropy", "distribution")}, []string{"length", "words"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Analyse(context.Background(), testChecker, "the quick brown fox", tt.opts...)
+			if err != nil {
+				t.Fatalf("Analyse() error = %v", err)
+			}
+			if got := analyserNames(results); !slices.Equal(got, tt.want) {
+				t.Errorf("Analyse() ran %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Analyse(context.Background(), testChecker, "input", WithoutAnalysers("nonsense")); err == nil {
+		t.Errorf("Analyse() returned no error for an unknown analyser")
+	}
+}
+
+func TestRegisterAnalyser(t *testing.T) {
+	reversed := NewAnalyser("reversed", PatternAnalysis, func(_ context.Context, _ Dictionary, _ Scorer, input string) []Analysis {
+		return []Analysis{{Message: "Reversed: " + Reverse(input), Confidence: 1}}
+	}, "custom")
+	withAnalysers(t, reversed)
+
+	results, err := Analyse(context.Background(), testChecker, "oof", WithAnalysers("length", "custom"))
+	if err != nil {
+		t.Fatalf("Analyse() error = %v", err)
+	}
+
+	want := Analysis{Analyser: "reversed", Category: PatternAnalysis, Message: "Reversed: foo", Confidence: 1}
+	if len(results) == 0 || results[len(results)-1] != want {
+		t.Errorf("Analyse() = %+v, want the registered analyser's finding last", results)
+	}
+
+	for _, name := range []string{"reversed", "Length", "", "two words"} {
+		duplicate := NewAnalyser(name, PatternAnalysis, func(context.Context, Dictionary, Scorer, string) []Analysis { return nil })
+		if err := RegisterAnalyser(duplicate); err == nil {
+			t.Errorf("RegisterAnalyser(%q) returned no error", name)
+		}
+	}
+}
+
+func TestAnalyse_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	stuck := NewAnalyser("stuck", PatternAnalysis, func(context.Context, Dictionary, Scorer, string) []Analysis {
+		<-release
+		return []Analysis{{Message: "Finished"}}
+	})
+	withAnalysers(t, stuck)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	results, err := Analyse(ctx, testChecker, "input", WithAnalysers("length", "stuck"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Analyse() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := analyserNames(results); !slices.Equal(got, []string{"length"}) {
+		t.Errorf("Analyse() returned findings from %v, want only the analysers that finished", got)
+	}
+}
+
+func TestParseAnalysisOptions(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantInput string
+		want      []string
+		wantErr   bool
+	}{
+		{"hello world", "hello world", []string{"entropy", "data", "caesar", "alternate", "primes", "common", "length", "distribution", "ciphers", "rle", "morse", "decode", "words", "palindromes"}, false},
+		{"only=length hello", "hello", []string{"length"}, false},
+		{"only=statistics skip=entropy,words  hello  world", " hello  world", []string{"length", "distribution"}, false},
+		{"aGVsbG8= only=length", "aGVsbG8= only=length", nil, false},
+		{"only= hello", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			input, opts, err := ParseAnalysisOptions(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAnalysisOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if input != tt.wantInput {
+				t.Errorf("ParseAnalysisOptions() input = %q, want %q", input, tt.wantInput)
+			}
+
+			o := &analysisOptions{}
+			for i := range opts {
+				opts[i](o)
+			}
+			selected, _ := selectAnalysers(Analysers(), o.only, o.skip)
+			var names []string
+			for _, a := range selected {
+				names = append(names, a.Name())
+			}
+			if tt.want != nil && !slices.Equal(names, tt.want) {
+				t.Errorf("ParseAnalysisOptions() selected %v, want %v", names, tt.want)
+			}
+			if tt.want == nil && len(opts) > 0 {
+				t.Errorf("ParseAnalysisOptions() returned options for %q", strings.Fields(tt.input)[0])
+			}
+		})
+	}
+}
+
+func TestAnalyse_CiphersIgnoreEncodings(t *testing.T) {
+	for _, input := range []string{
+		"NmM3NzIwNmM3NjIwNjQyMDc3NzU3ODc3NmIyMDc4NzE2Yzc5Njg3NTc2NjQ2ZjZmNjIyMDY0NjY2ZTcxNzI3YTZmNjg2NzZhNjg2Nw==",
+		"6974206973206120747275746820756e6976657273616c6c792061636b6e6f776c6564676564",
+	} {
+		if results, _ := Analyse(context.Background(), testChecker, input, WithAnalysers("ciphers")); len(results) > 0 {
+			t.Errorf("Analyse(%q) = %+v, want no cipher findings", input, results)
+		}
+	}
+}
+
+func TestWordCoverage(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{"foo bar", 1},
+		{"Foo, bar!", 1},
+		{"foo zzz", 0.5},
+		{"foobar", 0},
+		{"xquuxx", 4.0 / 6},
+		{"123", 0},
+	}
+
+	for _, tt := range tests {
+		if got := wordCoverage(testChecker, tt.text); got != tt.want {
+			t.Errorf("wordCoverage(%q) = %f, want %f", tt.text, got, tt.want)
+		}
+	}
+}
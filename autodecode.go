@@ -0,0 +1,396 @@
+package kowalski
+
+import (
+	"cmp"
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/csmith/cryptography"
+)
+
+// DecodeStep is a single layer of a DecodeChain.
+type DecodeStep struct {
+	// Decoder describes how the layer was decoded, such as "base64" or "rot13".
+	Decoder string `json:"decoder"`
+	// Output is the text after decoding the layer.
+	Output string `json:"output"`
+	// Score is how likely the output is to be English, according to the Scorer given to AutoDecode. It is scaled by
+	// the proportion of the output made up of letters, spaces and punctuation, as scorers generally ignore other
+	// characters and would otherwise rate text such as hex digits highly.
+	Score float64 `json:"score"`
+}
+
+// DecodeChain is a sequence of decodings that AutoDecode found for its input, outermost layer first.
+type DecodeChain struct {
+	Steps []DecodeStep `json:"steps"`
+}
+
+// Output returns the text produced by the final step of the chain.
+func (c DecodeChain) Output() string {
+	return c.Steps[len(c.Steps)-1].Output
+}
+
+// Score returns the score of the text produced by the final step of the chain.
+func (c DecodeChain) Score() float64 {
+	return c.Steps[len(c.Steps)-1].Score
+}
+
+// String describes the chain, such as "base64 → hex → rot3: hello world".
+func (c DecodeChain) String() string {
+	var decoders []string
+	for _, step := range c.Steps {
+		decoders = append(decoders, step.Decoder)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(decoders, " → "), c.Output())
+}
+
+// Decoder attempts to decode text in a particular encoding, returning each plausible decoding with its Decoder field
+// describing how it was made. Scores are filled in by AutoDecode. Decoders return nothing if the input isn't in their
+// encoding.
+type Decoder func(ctx context.Context, checker Dictionary, input string) []DecodeStep
+
+// Decoders contains the decoders that AutoDecode can use, by name.
+var Decoders = map[string]Decoder{
+	"a1z26":   decodeA1Z26,
+	"base32":  decodeBase32,
+	"base64":  decodeBase64,
+	"binary":  decodeBinary,
+	"decimal": decodeDecimal,
+	"hex":     decodeHex,
+	"morse":   decodeMorse,
+	"octal":   decodeOctal,
+	"reverse": decodeReverse,
+	"rle":     decodeRunLength,
+	"rot":     decodeRot,
+	"t9":      decodeT9,
+}
+
+// AutoDecodeOptions configures how AutoDecode searches for decodings.
+type AutoDecodeOptions struct {
+	// Scorer judges how likely each decoding is to be English. If nil, a DictionaryScorer is used.
+	Scorer Scorer
+	// MaxDepth is the maximum number of layers to decode. Defaults to 4.
+	MaxDepth int
+	// Results is the maximum number of chains to return. Defaults to 5.
+	Results int
+	// Decoders lists the names of the decoders to try, from Decoders. Defaults to all of them.
+	Decoders []string
+}
+
+// maxDecodeLength is the longest output a decoder may produce before it is discarded, to stop run-length encoding
+// and similar from producing enormous outputs.
+const maxDecodeLength = 10000
+
+// AutoDecode tries to peel layers of encoding from the input, such as base64 of hex of a Caesar shift. Every decoder
+// is tried on the input, then on each of the resulting texts, and so on until MaxDepth layers have been decoded or
+// the context is done. Chains whose final output scores better than the input are returned, best first; shorter
+// chains are preferred when scores are equal. An error is returned if the options name an unknown decoder, or if the
+// context is done before anything was found.
+func AutoDecode(ctx context.Context, checker Dictionary, input string, options AutoDecodeOptions) ([]DecodeChain, error) {
+	if options.Scorer == nil {
+		options.Scorer = DictionaryScorer(checker)
+	}
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = 4
+	}
+	if options.Results <= 0 {
+		options.Results = 5
+	}
+	if len(options.Decoders) == 0 {
+		options.Decoders = slices.Sorted(maps.Keys(Decoders))
+	}
+	for _, name := range options.Decoders {
+		if _, ok := Decoders[name]; !ok {
+			return nil, fmt.Errorf("unknown decoder: %s (expected one of: %s)", name, strings.Join(slices.Sorted(maps.Keys(Decoders)), ", "))
+		}
+	}
+
+	var (
+		baseline = decodeScore(options.Scorer, input)
+		seen     = map[string]bool{input: true}
+		frontier = []DecodeChain{{}}
+		chains   []DecodeChain
+	)
+
+search:
+	for range options.MaxDepth {
+		var next []DecodeChain
+		for _, chain := range frontier {
+			text := input
+			if len(chain.Steps) > 0 {
+				text = chain.Output()
+			}
+
+			for _, name := range options.Decoders {
+				if ctx.Err() != nil {
+					break search
+				}
+
+				for _, step := range Decoders[name](ctx, checker, text) {
+					if step.Output == "" || len(step.Output) > maxDecodeLength || seen[step.Output] {
+						continue
+					}
+					seen[step.Output] = true
+
+					step.Score = decodeScore(options.Scorer, step.Output)
+					decoded := DecodeChain{Steps: append(slices.Clone(chain.Steps), step)}
+					next = append(next, decoded)
+					if step.Score > baseline {
+						chains = append(chains, decoded)
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+
+	if len(chains) == 0 && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	slices.SortStableFunc(chains, func(a, b DecodeChain) int {
+		return cmp.Or(cmp.Compare(b.Score(), a.Score()), len(a.Steps)-len(b.Steps))
+	})
+	return chains[:min(len(chains), options.Results)], nil
+}
+
+// decodeScore scores the text, scaled by the proportion of it that is made up of letters, spaces and punctuation.
+func decodeScore(scorer Scorer, text string) float64 {
+	var textual, total int
+	for _, r := range text {
+		total++
+		if unicode.IsLetter(r) || unicode.IsSpace(r) || unicode.IsPunct(r) {
+			textual++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return scorer.Score(text) * float64(textual) / float64(total)
+}
+
+// isText determines whether decoded bytes look like text rather than arbitrary binary data: valid UTF-8 made only of
+// printable characters and whitespace.
+func isText(b []byte) bool {
+	if len(b) == 0 || !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// textStep returns a step for the decoded bytes if they look like text.
+func textStep(decoder string, b []byte) []DecodeStep {
+	if !isText(b) {
+		return nil
+	}
+	return []DecodeStep{{Decoder: decoder, Output: string(b)}}
+}
+
+var (
+	base64Regex = regexp.MustCompile(`^[A-Za-z0-9+/_-]+=*$`)
+	base32Regex = regexp.MustCompile(`^[A-Z2-7]+=*$`)
+	hexRegex    = regexp.MustCompile(`^(0x)?[0-9a-fA-F]+$`)
+
+	// numberSeparatorRegex matches the separators between numbers in decimal, octal and A1Z26 encodings.
+	numberSeparatorRegex = regexp.MustCompile(`[\s,;.\-]+`)
+)
+
+func decodeBase64(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	cleaned := strings.Join(strings.Fields(input), "")
+	if len(cleaned) < 4 || !base64Regex.MatchString(cleaned) {
+		return nil
+	}
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := encoding.DecodeString(cleaned); err == nil {
+			return textStep("base64", b)
+		}
+	}
+	return nil
+}
+
+func decodeBase32(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	cleaned := strings.ToUpper(strings.Join(strings.Fields(input), ""))
+	if len(cleaned) < 8 || !base32Regex.MatchString(cleaned) {
+		return nil
+	}
+
+	for _, encoding := range []*base32.Encoding{base32.StdEncoding, base32.StdEncoding.WithPadding(base32.NoPadding)} {
+		if b, err := encoding.DecodeString(cleaned); err == nil {
+			return textStep("base32", b)
+		}
+	}
+	return nil
+}
+
+func decodeHex(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	cleaned := strings.Join(strings.FieldsFunc(input, func(r rune) bool { return unicode.IsSpace(r) || r == ':' }), "")
+	if len(cleaned) < 4 || !hexRegex.MatchString(cleaned) {
+		return nil
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(cleaned, "0x"))
+	if err != nil {
+		return nil
+	}
+	return textStep("hex", b)
+}
+
+func decodeBinary(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || strings.Trim(strings.Join(fields, ""), "01") != "" {
+		return nil
+	}
+
+	// A single run of bits is split into bytes; otherwise each field is a character.
+	if len(fields) == 1 {
+		bits := fields[0]
+		if len(bits)%8 != 0 {
+			return nil
+		}
+		fields = nil
+		for i := 0; i < len(bits); i += 8 {
+			fields = append(fields, bits[i:i+8])
+		}
+	}
+
+	return numbersStep("binary", fields, 2)
+}
+
+func decodeOctal(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	fields := numberSeparatorRegex.Split(strings.TrimSpace(input), -1)
+	if len(fields) < 2 || strings.Trim(strings.Join(fields, ""), "01234567") != "" {
+		return nil
+	}
+	return numbersStep("octal", fields, 8)
+}
+
+func decodeDecimal(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	fields := numberSeparatorRegex.Split(strings.TrimSpace(input), -1)
+	if len(fields) < 2 {
+		return nil
+	}
+	return numbersStep("decimal", fields, 10)
+}
+
+// numbersStep converts each field to a character code in the given base, returning a step if the result is text.
+func numbersStep(decoder string, fields []string, base int) []DecodeStep {
+	b := make([]byte, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.ParseUint(field, base, 8)
+		if err != nil {
+			return nil
+		}
+		b = append(b, byte(n))
+	}
+	return textStep(decoder, b)
+}
+
+func decodeA1Z26(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	var (
+		words   []string
+		letters int
+	)
+	for _, word := range strings.Split(input, "/") {
+		b := &strings.Builder{}
+		for _, field := range numberSeparatorRegex.Split(strings.TrimSpace(word), -1) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > 26 {
+				return nil
+			}
+			b.WriteByte(byte('a' + n - 1))
+			letters++
+		}
+		words = append(words, b.String())
+	}
+
+	if letters < 2 {
+		return nil
+	}
+	return []DecodeStep{{Decoder: "a1z26", Output: strings.Join(words, " ")}}
+}
+
+func decodeRot(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	if !strings.ContainsFunc(input, func(r rune) bool { return r < unicode.MaxASCII && unicode.IsLetter(r) }) {
+		return nil
+	}
+
+	var steps []DecodeStep
+	for n, shifted := range cryptography.CaesarShifts([]byte(input)) {
+		if n > 0 {
+			steps = append(steps, DecodeStep{Decoder: fmt.Sprintf("rot%d", n), Output: string(shifted)})
+		}
+	}
+	return steps
+}
+
+// decodeReverse reverses the input. Unlike Reverse, punctuation is kept, as it may be part of another encoding.
+func decodeReverse(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	reversed := []rune(input)
+	slices.Reverse(reversed)
+	return []DecodeStep{{Decoder: "reverse", Output: string(reversed)}}
+}
+
+func decodeMorse(_ context.Context, _ Dictionary, input string) []DecodeStep {
+	if symbols := morseSymbolsIn(input); len(symbols) == 0 || len(symbols) > 3 {
+		return nil
+	}
+
+	var steps []DecodeStep
+	for _, mapping := range MorseMappings(input) {
+		if !parseMorse(mapping.Signals).spaced {
+			continue
+		}
+		if decoded, err := DecodeMorse(mapping.Signals); err == nil {
+			decoder := "morse"
+			if mapping.Description != "standard" {
+				decoder = fmt.Sprintf("morse (%s)", mapping.Description)
+			}
+			steps = append(steps, DecodeStep{Decoder: decoder, Output: decoded})
+		}
+	}
+	return steps
+}
+
+// maxT9Decodings is the number of dictionary decodings of T9 input that are tried.
+const maxT9Decodings = 3
+
+func decodeT9(ctx context.Context, checker Dictionary, input string) []DecodeStep {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || strings.Trim(strings.Join(fields, ""), "0123456789") != "" {
+		return nil
+	}
+
+	options := T9Options{MultiTap: looksMultiTap(fields, ITULayout)}
+	var steps []DecodeStep
+	for decoded := range FromT9Seq(ctx, checker, input, options) {
+		steps = append(steps, DecodeStep{Decoder: "t9", Output: decoded})
+		if len(steps) == maxT9Decodings {
+			break
+		}
+	}
+	return steps
+}
+
+func decodeRunLength(ctx context.Context, checker Dictionary, input string) []DecodeStep {
+	var steps []DecodeStep
+	for _, result := range analyseRunLengthEncoding(ctx, checker, nil, input) {
+		steps = append(steps, DecodeStep{Decoder: "rle", Output: result.Output})
+	}
+	return steps
+}
@@ -0,0 +1,138 @@
+package kowalski
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecoders(t *testing.T) {
+	tests := []struct {
+		decoder string
+		input   string
+		want    string
+	}{
+		{"a1z26", "6 15 15 / 2 1 18", "foo bar"},
+		{"a1z26", "6-15-15", "foo"},
+		{"base32", "NBSWY3DPEB3W64TMMQ======", "hello world"},
+		{"base32", "NBSWY3DPEB3W64TMMQ", "hello world"},
+		{"base64", "aGVsbG8gd29ybGQ=", "hello world"},
+		{"base64", "aGVsbG8gd29ybGQ", "hello world"},
+		{"binary", "01101000 01101001", "hi"},
+		{"binary", "0110100001101001", "hi"},
+		{"decimal", "104, 105", "hi"},
+		{"hex", "6869207468657265", "hi there"},
+		{"hex", "68:69", "hi"},
+		{"morse", ".... .. / - .... . .-. .", "hi there"},
+		{"morse", "0000 00 / 1 0000 0 010 0", "hi there"},
+		{"octal", "150 151 40 164 150 145 162 145", "hi there"},
+		{"reverse", "oof", "foo"},
+		{"reverse", "=kGa", "aGk="},
+		{"rle", "3a2b", "aaabb"},
+		{"rot", "uryyb", "hello"},
+		{"t9", "366 227", "foo bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decoder+" "+tt.input, func(t *testing.T) {
+			var outputs []string
+			for _, step := range Decoders[tt.decoder](context.Background(), testChecker, tt.input) {
+				outputs = append(outputs, step.Output)
+			}
+			if !slices.Contains(outputs, tt.want) {
+				t.Errorf("Decoders[%q](%q) = %q, want %q", tt.decoder, tt.input, outputs, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoders_Rejected(t *testing.T) {
+	tests := []struct {
+		decoder string
+		input   string
+	}{
+		{"a1z26", "6 15 27"},
+		{"base32", "hello world"},
+		{"base64", "this is not base64"},
+		{"base64", "AAECAwQF"},
+		{"binary", "0110100"},
+		{"decimal", "104"},
+		{"hex", "not hex"},
+		{"morse", "hello world"},
+		{"octal", "150 158"},
+		{"rot", "12345"},
+		{"t9", "12a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decoder+" "+tt.input, func(t *testing.T) {
+			if got := Decoders[tt.decoder](context.Background(), testChecker, tt.input); len(got) > 0 {
+				t.Errorf("Decoders[%q](%q) = %+v, want no decodings", tt.decoder, tt.input, got)
+			}
+		})
+	}
+}
+
+func TestAutoDecode(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4)
+
+	// "it is a truth universally acknowledged", Caesar shifted by 3, hex encoded, then base64 encoded
+	input := "NmM3NzIwNmM3NjIwNjQyMDc3NzU3ODc3NmIyMDc4NzE2Yzc5Njg3NTc2NjQ2ZjZmNjIyMDY0NjY2ZTcxNzI3YTZmNjg2NzZhNjg2Nw=="
+
+	chains, err := AutoDecode(context.Background(), testChecker, input, AutoDecodeOptions{Scorer: model})
+	if err != nil {
+		t.Fatalf("AutoDecode() error = %v", err)
+	}
+
+	if len(chains) == 0 || chains[0].String() != "base64 → hex → rot23: it is a truth universally acknowledged" {
+		t.Fatalf("AutoDecode() = %v, want base64 → hex → rot23 first", chains)
+	}
+	if len(chains) > 5 {
+		t.Errorf("AutoDecode() returned %d chains, want at most 5", len(chains))
+	}
+	if score := chains[0].Score(); score < 0.9 {
+		t.Errorf("AutoDecode() scored the plaintext %f, want at least 0.9", score)
+	}
+
+	shallow, _ := AutoDecode(context.Background(), testChecker, input, AutoDecodeOptions{Scorer: model, MaxDepth: 2})
+	for _, chain := range shallow {
+		if len(chain.Steps) > 2 {
+			t.Errorf("AutoDecode() returned %v, want at most 2 steps", chain)
+		}
+	}
+
+	limited, _ := AutoDecode(context.Background(), testChecker, input, AutoDecodeOptions{Scorer: model, Decoders: []string{"base64", "hex"}})
+	for _, chain := range limited {
+		if chain.Output() == "it is a truth universally acknowledged" {
+			t.Errorf("AutoDecode() found %v without the rot decoder", chain)
+		}
+	}
+}
+
+func TestAutoDecode_Errors(t *testing.T) {
+	if _, err := AutoDecode(context.Background(), testChecker, "aGk=", AutoDecodeOptions{Decoders: []string{"rot47"}}); err == nil {
+		t.Errorf("AutoDecode() returned no error for an unknown decoder")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AutoDecode(ctx, testChecker, "aGk=", AutoDecodeOptions{}); err == nil {
+		t.Errorf("AutoDecode() returned no error for a cancelled context")
+	}
+}
+
+func TestAnalyse_Encodings(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4)
+
+	input := "NmM3NzIwNmM3NjIwNjQyMDc3NzU3ODc3NmIyMDc4NzE2Yzc5Njg3NTc2NjQ2ZjZmNjIyMDY0NjY2ZTcxNzI3YTZmNjg2NzZhNjg2Nw=="
+	results, err := Analyse(context.Background(), testChecker, input, WithScorer(model), WithAnalysers("decode"))
+	if err != nil {
+		t.Fatalf("Analyse() error = %v", err)
+	}
+
+	want := "Might be encoded as base64 → hex → rot23: it is a truth universally acknowledged"
+	if len(results) == 0 || !strings.HasPrefix(results[0].Message, want) || results[0].Command != "decode "+input {
+		t.Errorf("Analyse() = %+v, want a result starting %q", results, want)
+	}
+}
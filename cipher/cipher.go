@@ -0,0 +1,161 @@
+// Package cipher implements classical pen-and-paper ciphers.
+package cipher
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Cipher encrypts and decrypts text with a particular key.
+type Cipher interface {
+	// Encrypt enciphers the plaintext.
+	Encrypt(plaintext string) (string, error)
+	// Decrypt deciphers the ciphertext.
+	Decrypt(ciphertext string) (string, error)
+}
+
+// Names lists the ciphers that can be created with Parse.
+var Names = []string{"adfgvx", "adfgx", "affine", "atbash", "beaufort", "playfair", "polybius", "substitution", "vigenere"}
+
+// Parse creates a cipher from a textual description, returning the cipher and the remaining text. The first field
+// names the cipher (one of Names), and fields in the form "key=value" configure it:
+//
+//   - key: the keyword for Vigenère, Beaufort, Playfair, Polybius and Substitution, or the transposition key for
+//     ADFG(V)X
+//   - square: the keyword used to mix the Polybius square for ADFG(V)X
+//   - a, b: the multiplier and offset for Affine
+//
+// The remaining fields are joined with spaces. For example, "vigenere key=lemon attack at dawn" returns a Vigenère
+// cipher with the key "lemon" and the text "attack at dawn".
+func Parse(input string) (Cipher, string, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("no cipher given (expected one of: %s)", strings.Join(Names, ", "))
+	}
+
+	name := strings.ToLower(fields[0])
+	if !slices.Contains(Names, name) {
+		return nil, "", fmt.Errorf("unknown cipher: %s (expected one of: %s)", fields[0], strings.Join(Names, ", "))
+	}
+
+	var (
+		options = make(map[string]string)
+		rest    []string
+	)
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
+
+		key = strings.ToLower(key)
+		if !slices.Contains([]string{"key", "square", "a", "b"}, key) {
+			return nil, "", fmt.Errorf("unknown option: %s", key)
+		}
+		options[key] = value
+	}
+
+	var (
+		c   Cipher
+		err error
+	)
+	switch name {
+	case "adfgvx":
+		c, err = NewADFGVX(options["square"], options["key"])
+	case "adfgx":
+		c, err = NewADFGX(options["square"], options["key"])
+	case "affine":
+		var a, b int
+		if a, err = intOption(options, "a"); err == nil {
+			if b, err = intOption(options, "b"); err == nil {
+				c, err = NewAffine(a, b)
+			}
+		}
+	case "atbash":
+		c = NewAtbash()
+	case "beaufort":
+		c, err = NewBeaufort(options["key"])
+	case "playfair":
+		c, err = NewPlayfair(options["key"])
+	case "polybius":
+		c, err = NewPolybius(options["key"])
+	case "substitution":
+		c, err = NewSubstitution(options["key"])
+	case "vigenere":
+		c, err = NewVigenere(options["key"])
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c, strings.Join(rest, " "), nil
+}
+
+// intOption parses the named option as an integer.
+func intOption(options map[string]string, name string) (int, error) {
+	value, ok := options[name]
+	if !ok {
+		return 0, fmt.Errorf("option %s is required", name)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("option %s requires a number, got %q", name, value)
+	}
+	return n, nil
+}
+
+// letterIndex returns the position of the letter in the alphabet (0 to 25), or -1 if it isn't an ASCII letter.
+func letterIndex(r rune) int {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return int(r - 'a')
+	case r >= 'A' && r <= 'Z':
+		return int(r - 'A')
+	default:
+		return -1
+	}
+}
+
+// shiftLetter replaces the letter with the one at the given position in the alphabet, keeping its case.
+func shiftLetter(r rune, index int) rune {
+	index = ((index % 26) + 26) % 26
+	if r >= 'A' && r <= 'Z' {
+		return rune('A' + index)
+	}
+	return rune('a' + index)
+}
+
+// substitute replaces each letter in the input using the mapping function, which is given the letter's position in
+// the alphabet and the number of letters seen before it. Other characters are left unchanged.
+func substitute(input string, mapping func(index, count int) int) string {
+	b := &strings.Builder{}
+	count := 0
+	for _, r := range input {
+		if index := letterIndex(r); index != -1 {
+			b.WriteRune(shiftLetter(r, mapping(index, count)))
+			count++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// keyShifts converts a keyword into a list of shifts (a=0, b=1, ...), ignoring characters other than letters.
+func keyShifts(key string) ([]int, error) {
+	var shifts []int
+	for _, r := range key {
+		if index := letterIndex(r); index != -1 {
+			shifts = append(shifts, index)
+		}
+	}
+
+	if len(shifts) == 0 {
+		return nil, fmt.Errorf("a key containing letters is required")
+	}
+	return shifts, nil
+}
@@ -0,0 +1,132 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCiphers(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		plaintext  string
+		ciphertext string
+		decrypted  string
+	}{
+		{"atbash", "atbash", "Hello, World", "Svool, Dliow", "Hello, World"},
+		{"affine", "affine a=5 b=8", "AFFINE CIPHER", "IHHWVC SWFRCP", "AFFINE CIPHER"},
+		{"vigenere", "vigenere key=LEMON", "attack at dawn", "lxfopv ef rnhr", "attack at dawn"},
+		{"beaufort", "beaufort key=key", "abc", "kdw", "abc"},
+		{"playfair", "playfair key=playfair_example", "Hide the gold in the tree stump", "BMODZBXDNABEKUDMUIXMMOUVIF", "hidethegoldinthetrexestump"},
+		{"substitution", "substitution key=zebras", "flee at once", "siaa zq lkba", "flee at once"},
+		{"polybius", "polybius", "hello", "23 15 31 31 34", "hello"},
+		{"keyed polybius", "polybius key=zebra", "hello", "25 12 33 33 41", "hello"},
+		{"adfgx", "adfgx square=btalpdhozkqfvsngicuxmrewy key=CARGO", "attack at once", "FAXDFADDDGDGFFFAFAXAFAFX", "attackatonce"},
+		{"adfgvx", "adfgvx square=na1c3h8tb2ome5wrpd4f6g7i9j0klqsuvxyz key=PRIVACY", "attack at 1200am", "DGDDDAGDDGAFADDFDADVDVFAADVX", "attackat1200am"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ciphertext, err := c.Encrypt(tt.plaintext)
+			if err != nil || ciphertext != tt.ciphertext {
+				t.Errorf("Encrypt() = %q, %v, want %q", ciphertext, err, tt.ciphertext)
+			}
+
+			decrypted, err := c.Decrypt(tt.ciphertext)
+			if err != nil || decrypted != tt.decrypted {
+				t.Errorf("Decrypt() = %q, %v, want %q", decrypted, err, tt.decrypted)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantText string
+		wantErr  bool
+	}{
+		{"text", "Vigenere key=lemon attack  at dawn", "attack at dawn", false},
+		{"no cipher", "", "", true},
+		{"unknown cipher", "enigma attack", "", true},
+		{"unknown option", "atbash rotors=3 attack", "", true},
+		{"missing key", "vigenere attack", "", true},
+		{"missing affine option", "affine a=5 attack", "", true},
+		{"affine not coprime", "affine a=13 b=1 attack", "", true},
+		{"missing transposition key", "adfgx square=abc attack", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, text, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if text != tt.wantText {
+				t.Errorf("Parse() text = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	playfair, _ := NewPlayfair("key")
+	adfgx, _ := NewADFGX("", "key")
+	polybius, _ := NewPolybius("")
+
+	tests := []struct {
+		name       string
+		cipher     Cipher
+		ciphertext string
+	}{
+		{"playfair odd length", playfair, "ABC"},
+		{"playfair doubled letter", playfair, "AABC"},
+		{"adfgx invalid character", adfgx, "ADFGQ"},
+		{"adfgx odd length", adfgx, "ADF"},
+		{"polybius odd digits", polybius, "123"},
+		{"polybius out of range", polybius, "16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cipher.Decrypt(tt.ciphertext); err == nil {
+				t.Errorf("Decrypt(%q) returned no error", tt.ciphertext)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	plaintext := "the quick brown fox jumps over the lazy dog"
+	for _, name := range Names {
+		t.Run(name, func(t *testing.T) {
+			c, _, err := Parse(name + " key=zebras square=cipher a=7 b=3")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ciphertext, err := c.Encrypt(plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			decrypted, err := c.Decrypt(ciphertext)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+
+			// Some ciphers drop spaces, merge I and J, or insert padding Xs
+			normalise := strings.NewReplacer(" ", "", "j", "i", "x", "").Replace
+			got, want := normalise(decrypted), normalise(plaintext)
+			if got != want {
+				t.Errorf("round trip = %q, want %q", got, want)
+			}
+		})
+	}
+}
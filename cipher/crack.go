@@ -0,0 +1,493 @@
+package cipher
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/csmith/kowalski/v6/data"
+)
+
+// Candidate is a possible decryption of a ciphertext found by one of the crackers.
+type Candidate struct {
+	// Key is the recovered key, in the form accepted by the corresponding cipher (so a Vigenère keyword, or the
+	// 26-letter alphabet for Substitution).
+	Key string `json:"key"`
+	// Plaintext is the ciphertext decrypted with the key.
+	Plaintext string `json:"plaintext"`
+	// Score is the fitness of the plaintext; higher scores are more likely to be English.
+	Score float64 `json:"score"`
+}
+
+// Fitness scores how much a text resembles English. Higher scores are better; the scale is up to the function.
+type Fitness func(text string) float64
+
+// Cracker attempts to recover the plaintext of a ciphertext without knowing the key.
+type Cracker func(ctx context.Context, ciphertext string, options CrackOptions) ([]Candidate, error)
+
+// Crackers contains the available crackers, by the name of the cipher they attack.
+var Crackers = map[string]Cracker{
+	"substitution": CrackSubstitution,
+	"vigenere":     CrackVigenere,
+}
+
+// ParseCracker finds the cracker named by the first field of the input (one of the keys of Crackers), returning it
+// along with the remaining fields joined with spaces.
+func ParseCracker(input string) (Cracker, string, error) {
+	names := slices.Sorted(maps.Keys(Crackers))
+
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("no cipher given (expected one of: %s)", strings.Join(names, ", "))
+	}
+
+	cracker, ok := Crackers[strings.ToLower(fields[0])]
+	if !ok {
+		return nil, "", fmt.Errorf("unable to crack cipher: %s (expected one of: %s)", fields[0], strings.Join(names, ", "))
+	}
+	return cracker, strings.Join(fields[1:], " "), nil
+}
+
+// CrackOptions configures the crackers.
+type CrackOptions struct {
+	// Fitness ranks candidate plaintexts. If nil, BigramFitness is used.
+	Fitness Fitness
+	// Results is the maximum number of candidates to return. If zero, 5 are returned.
+	Results int
+	// MaxKeyLength is the longest Vigenère key to consider. If zero, keys of up to 20 letters are considered.
+	MaxKeyLength int
+	// Restarts is the number of times the substitution cracker starts climbing from a new random key. If zero, 30
+	// restarts are made. The cracker also stops if the context's deadline passes.
+	Restarts int
+}
+
+func (o CrackOptions) fitness() Fitness {
+	if o.Fitness == nil {
+		return BigramFitness
+	}
+	return o.Fitness
+}
+
+func (o CrackOptions) results() int {
+	if o.Results <= 0 {
+		return 5
+	}
+	return o.Results
+}
+
+func (o CrackOptions) maxKeyLength() int {
+	if o.MaxKeyLength <= 0 {
+		return 20
+	}
+	return o.MaxKeyLength
+}
+
+func (o CrackOptions) restarts() int {
+	if o.Restarts <= 0 {
+		return 30
+	}
+	return o.Restarts
+}
+
+var (
+	// bigramLogs contains the log10 probability of each bigram, indexed by the position of its letters in the
+	// alphabet.
+	bigramLogs [26][26]float64
+	// letterFrequencies contains the relative frequency of each letter in English, derived from the bigram data.
+	letterFrequencies [26]float64
+)
+
+func init() {
+	total := 0.0
+	for i := range bigramLogs {
+		for j := range bigramLogs[i] {
+			frequency := data.Bigrams[string([]byte{byte('A' + i), byte('A' + j)})]
+			bigramLogs[i][j] = math.Log10(math.Max(frequency, 0.0001))
+			letterFrequencies[i] += frequency
+			total += frequency
+		}
+	}
+
+	for i := range letterFrequencies {
+		letterFrequencies[i] /= total
+	}
+}
+
+// BigramFitness returns the mean log10 frequency of the bigrams in the text, using the English bigram frequencies
+// in data.Bigrams. Characters other than letters are ignored. English text typically scores around -1.5, and
+// random letters around -2.5.
+func BigramFitness(text string) float64 {
+	letters := letterIndices(text)
+	if len(letters) < 2 {
+		return math.Inf(-1)
+	}
+
+	score := 0.0
+	for i := 1; i < len(letters); i++ {
+		score += bigramLogs[letters[i-1]][letters[i]]
+	}
+	return score / float64(len(letters)-1)
+}
+
+// CrackVigenere attempts to find the key used to encrypt a Vigenère ciphertext. Likely key lengths are estimated
+// from the distances between repeated sequences (the Kasiski examination) and from the index of coincidence of the
+// letters each key letter would have encrypted. For each likely length, every key letter is then chosen by comparing
+// the frequency of the letters it decrypts with English. Candidates are returned with the best first.
+//
+// The ciphertext needs to be reasonably long: a few times the length of the key at the very least. If the context
+// is cancelled, ctx.Err() is returned.
+func CrackVigenere(ctx context.Context, ciphertext string, options CrackOptions) ([]Candidate, error) {
+	letters := letterIndices(ciphertext)
+	if len(letters) < 2 {
+		return nil, nil
+	}
+
+	fitness := options.fitness()
+	var candidates []Candidate
+	for _, length := range vigenereKeyLengths(letters, options.maxKeyLength()) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		key := minimalPeriod(vigenereKey(letters, length))
+		if slices.ContainsFunc(candidates, func(c Candidate) bool { return c.Key == key }) {
+			continue
+		}
+
+		v, err := NewVigenere(key)
+		if err != nil {
+			return nil, err
+		}
+		plaintext, _ := v.Decrypt(ciphertext)
+		candidates = append(candidates, Candidate{Key: key, Plaintext: plaintext, Score: fitness(plaintext)})
+	}
+
+	return rankCandidates(candidates, options.results()), nil
+}
+
+// vigenereKeyLengths returns the key lengths worth trying: those whose columns have the highest index of
+// coincidence, plus those that divide the most distances between repeated sequences.
+func vigenereKeyLengths(letters []int, maxLength int) []int {
+	maxLength = min(maxLength, len(letters)/2)
+	if maxLength < 1 {
+		return []int{1}
+	}
+
+	type estimate struct {
+		length       int
+		ioc, kasiski float64
+	}
+
+	distances := repeatDistances(letters, 3)
+	estimates := make([]estimate, maxLength)
+	for length := 1; length <= maxLength; length++ {
+		e := estimate{length: length}
+		for column := range length {
+			var counts [26]int
+			total := 0
+			for i := column; i < len(letters); i += length {
+				counts[letters[i]]++
+				total++
+			}
+			e.ioc += indexOfCoincidence(counts, total) / float64(length)
+		}
+
+		for _, distance := range distances {
+			if distance%length == 0 {
+				e.kasiski++
+			}
+		}
+		if len(distances) > 0 {
+			e.kasiski /= float64(len(distances))
+		}
+		estimates[length-1] = e
+	}
+
+	var lengths []int
+	add := func(count int, compare func(a, b estimate) int) {
+		sorted := slices.Clone(estimates)
+		slices.SortStableFunc(sorted, compare)
+		for _, e := range sorted[:min(count, len(sorted))] {
+			if !slices.Contains(lengths, e.length) {
+				lengths = append(lengths, e.length)
+			}
+		}
+	}
+
+	add(5, func(a, b estimate) int { return descending(a.ioc, b.ioc) })
+	if len(distances) > 0 {
+		// Every distance is divisible by 1 (and most by 2), so only lengths above that are informative.
+		add(3, func(a, b estimate) int {
+			if (a.length <= 2) != (b.length <= 2) {
+				if a.length <= 2 {
+					return 1
+				}
+				return -1
+			}
+			return descending(a.kasiski, b.kasiski)
+		})
+	}
+	return lengths
+}
+
+// repeatDistances returns the distance between each pair of consecutive occurrences of the same sequence of letters
+// of the given length.
+func repeatDistances(letters []int, length int) []int {
+	var (
+		distances []int
+		last      = make(map[string]int)
+		sequence  = make([]byte, length)
+	)
+
+	for i := 0; i+length <= len(letters); i++ {
+		for j := range sequence {
+			sequence[j] = byte(letters[i+j])
+		}
+		if previous, ok := last[string(sequence)]; ok {
+			distances = append(distances, i-previous)
+		}
+		last[string(sequence)] = i
+	}
+	return distances
+}
+
+// vigenereKey chooses each letter of a key of the given length by finding the shift that makes the letters in its
+// column most closely resemble English, measured by the chi-squared statistic.
+func vigenereKey(letters []int, length int) string {
+	key := make([]byte, length)
+	for column := range length {
+		var counts [26]int
+		total := 0
+		for i := column; i < len(letters); i += length {
+			counts[letters[i]]++
+			total++
+		}
+
+		best, bestChi := 0, math.Inf(1)
+		for shift := range 26 {
+			chi := 0.0
+			for plain := range 26 {
+				expected := letterFrequencies[plain] * float64(total)
+				difference := float64(counts[(plain+shift)%26]) - expected
+				chi += difference * difference / expected
+			}
+			if chi < bestChi {
+				best, bestChi = shift, chi
+			}
+		}
+		key[column] = byte('A' + best)
+	}
+	return string(key)
+}
+
+// minimalPeriod returns the shortest string that repeats to make the key, so that "LEMONLEMON" becomes "LEMON".
+func minimalPeriod(key string) string {
+	for length := 1; length < len(key); length++ {
+		if len(key)%length == 0 && strings.Repeat(key[:length], len(key)/length) == key {
+			return key[:length]
+		}
+	}
+	return key
+}
+
+// CrackSubstitution attempts to find the key used to encrypt a monoalphabetic substitution ciphertext. It uses
+// hill-climbing: starting from a key that matches the ciphertext's letter frequencies to English (and then from
+// random keys), pairs of letters in the key are swapped whenever doing so makes the decryption's bigrams more like
+// English. If a Fitness function is given in the options, the best keys are then refined by climbing further using
+// it, which is slower but lets a stronger model (such as quadgrams) correct the bigrams' mistakes. The distinct keys
+// found are returned with the best first.
+//
+// Results are deterministic for a given ciphertext and options. Climbing stops when the restarts are exhausted or
+// the context is done; the best keys found so far are returned, and ctx.Err() is only returned if the context ended
+// before any were found. Longer ciphertexts (a few hundred letters) are recovered much more reliably than short ones.
+func CrackSubstitution(ctx context.Context, ciphertext string, options CrackOptions) ([]Candidate, error) {
+	letters := letterIndices(ciphertext)
+	if len(letters) < 2 {
+		return nil, nil
+	}
+
+	// Counting the bigrams in the ciphertext once means each key can be scored without decrypting the text.
+	var (
+		pairs   [26][26]float64
+		singles [26]int
+	)
+	for i := range letters {
+		singles[letters[i]]++
+		if i > 0 {
+			pairs[letters[i-1]][letters[i]]++
+		}
+	}
+
+	// bigramScore returns the bigram fitness of decrypting with the key, which gives the plaintext letter for each
+	// ciphertext letter.
+	bigramScore := func(key *[26]int) float64 {
+		total := 0.0
+		for i := range pairs {
+			for j := range pairs[i] {
+				if pairs[i][j] > 0 {
+					total += pairs[i][j] * bigramLogs[key[i]][key[j]]
+				}
+			}
+		}
+		return total
+	}
+
+	type climbed struct {
+		key   [26]int
+		score float64
+	}
+
+	var (
+		random = rand.New(rand.NewPCG(uint64(len(letters)), 1))
+		found  []climbed
+		seen   = make(map[[26]int]bool)
+	)
+
+	for restart := range options.restarts() {
+		if ctx.Err() != nil {
+			break
+		}
+
+		key := frequencyKey(singles)
+		if restart > 0 {
+			random.Shuffle(len(key), func(i, j int) { key[i], key[j] = key[j], key[i] })
+		}
+
+		score := climb(ctx, &key, bigramScore)
+		if !seen[key] {
+			seen[key] = true
+			found = append(found, climbed{key: key, score: score})
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, ctx.Err()
+	}
+
+	slices.SortStableFunc(found, func(a, b climbed) int { return descending(a.score, b.score) })
+	found = found[:min(options.results(), len(found))]
+
+	fitness := options.fitness()
+	candidates := make([]Candidate, 0, len(found))
+	for _, f := range found {
+		if options.Fitness != nil {
+			climb(ctx, &f.key, func(key *[26]int) float64 {
+				return fitness(decryptSubstitution(*key, ciphertext))
+			})
+		}
+
+		candidate := substitutionCandidate(f.key, ciphertext, fitness)
+		if !slices.ContainsFunc(candidates, func(c Candidate) bool { return c.Key == candidate.Key }) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return rankCandidates(candidates, options.results()), nil
+}
+
+// climb repeatedly swaps pairs of letters in the key, keeping each swap that increases its score, until no swap
+// helps or the context is done. Returns the final score.
+func climb(ctx context.Context, key *[26]int, score func(key *[26]int) float64) float64 {
+	best := score(key)
+	for improved := true; improved && ctx.Err() == nil; {
+		improved = false
+		for i := range key {
+			for j := i + 1; j < len(key); j++ {
+				key[i], key[j] = key[j], key[i]
+				if s := score(key); s > best {
+					best = s
+					improved = true
+				} else {
+					key[i], key[j] = key[j], key[i]
+				}
+			}
+		}
+	}
+	return best
+}
+
+// frequencyKey returns the key that maps the most common ciphertext letter to the most common English letter, the
+// second most common to the second, and so on.
+func frequencyKey(counts [26]int) [26]int {
+	cipherOrder := make([]int, 26)
+	plainOrder := make([]int, 26)
+	for i := range 26 {
+		cipherOrder[i], plainOrder[i] = i, i
+	}
+
+	slices.SortStableFunc(cipherOrder, func(a, b int) int { return counts[b] - counts[a] })
+	slices.SortStableFunc(plainOrder, func(a, b int) int {
+		return descending(letterFrequencies[a], letterFrequencies[b])
+	})
+
+	var key [26]int
+	for i := range cipherOrder {
+		key[cipherOrder[i]] = plainOrder[i]
+	}
+	return key
+}
+
+// substitutionCandidate decrypts the ciphertext with a key giving the plaintext letter for each ciphertext letter,
+// and returns it along with the key in the form NewSubstitution accepts.
+func substitutionCandidate(key [26]int, ciphertext string, fitness Fitness) Candidate {
+	encryption := make([]byte, 26)
+	for cipherLetter, plainLetter := range key {
+		encryption[plainLetter] = byte('A' + cipherLetter)
+	}
+
+	plaintext := decryptSubstitution(key, ciphertext)
+	return Candidate{Key: string(encryption), Plaintext: plaintext, Score: fitness(plaintext)}
+}
+
+// decryptSubstitution decrypts the ciphertext with a key giving the plaintext letter for each ciphertext letter.
+func decryptSubstitution(key [26]int, ciphertext string) string {
+	return substitute(ciphertext, func(index, _ int) int {
+		return key[index]
+	})
+}
+
+// rankCandidates sorts the candidates with the highest score first, and returns at most limit of them.
+func rankCandidates(candidates []Candidate, limit int) []Candidate {
+	slices.SortStableFunc(candidates, func(a, b Candidate) int { return descending(a.Score, b.Score) })
+	return candidates[:min(limit, len(candidates))]
+}
+
+// descending compares two numbers so that sorting puts the largest first.
+func descending(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// letterIndices returns the position in the alphabet of each letter in the text, ignoring other characters.
+func letterIndices(text string) []int {
+	var letters []int
+	for _, r := range text {
+		if index := letterIndex(r); index != -1 {
+			letters = append(letters, index)
+		}
+	}
+	return letters
+}
+
+// indexOfCoincidence returns the probability that two letters chosen at random are the same, normalised so that
+// random text scores 1. English text scores around 1.73.
+func indexOfCoincidence(counts [26]int, total int) float64 {
+	if total < 2 {
+		return 0
+	}
+
+	sum := 0
+	for _, count := range counts {
+		sum += count * (count - 1)
+	}
+	return 26 * float64(sum) / float64(total*(total-1))
+}
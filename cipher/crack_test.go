@@ -0,0 +1,125 @@
+package cipher
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const crackPlaintext = "It is a truth universally acknowledged, that a single man in possession of a good fortune, must be " +
+	"in want of a wife. However little known the feelings or views of such a man may be on his first entering a " +
+	"neighbourhood, this truth is so well fixed in the minds of the surrounding families, that he is considered the " +
+	"rightful property of some one or other of their daughters. My dear Mr. Bennet, said his lady to him one day, " +
+	"have you heard that Netherfield Park is let at last? Mr. Bennet replied that he had not. But it is, returned " +
+	"she; for Mrs. Long has just been here, and she told me all about it."
+
+func TestBigramFitness(t *testing.T) {
+	english := BigramFitness(crackPlaintext)
+	shuffled := BigramFitness("qzxj vkwp fmyb gxqz jvkw bmfp zqxj")
+	if english <= shuffled {
+		t.Errorf("BigramFitness(english) = %f, want more than BigramFitness(random) = %f", english, shuffled)
+	}
+}
+
+func TestCrackVigenere(t *testing.T) {
+	tests := []string{"LEMON", "KOWALSKI", "CRYPTOGRAPHY"}
+	for _, key := range tests {
+		t.Run(key, func(t *testing.T) {
+			v, _ := NewVigenere(key)
+			ciphertext, _ := v.Encrypt(crackPlaintext)
+
+			candidates, err := CrackVigenere(context.Background(), ciphertext, CrackOptions{})
+			if err != nil {
+				t.Fatalf("CrackVigenere() error = %v", err)
+			}
+			if len(candidates) == 0 {
+				t.Fatalf("CrackVigenere() returned no candidates")
+			}
+			if candidates[0].Key != key || candidates[0].Plaintext != crackPlaintext {
+				t.Errorf("CrackVigenere() best = %q: %q, want %q", candidates[0].Key, candidates[0].Plaintext, key)
+			}
+		})
+	}
+}
+
+func TestCrackVigenere_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := CrackVigenere(ctx, crackPlaintext, CrackOptions{}); err == nil {
+		t.Errorf("CrackVigenere() returned no error for a cancelled context")
+	}
+}
+
+func TestCrackSubstitution(t *testing.T) {
+	const key = "QWERTYUIOPASDFGHJKLZXCVBNM"
+	s, _ := NewSubstitution(key)
+	ciphertext, _ := s.Encrypt(crackPlaintext)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	candidates, err := CrackSubstitution(ctx, ciphertext, CrackOptions{})
+	if err != nil {
+		t.Fatalf("CrackSubstitution() error = %v", err)
+	}
+	if len(candidates) == 0 {
+		t.Fatalf("CrackSubstitution() returned no candidates")
+	}
+
+	// Bigrams alone won't perfectly place rare letters, so check that most of the text is recovered.
+	best := strings.ToLower(candidates[0].Plaintext)
+	want := strings.ToLower(crackPlaintext)
+	correct := 0
+	for i := range want {
+		if best[i] == want[i] {
+			correct++
+		}
+	}
+	if float64(correct)/float64(len(want)) < 0.9 {
+		t.Errorf("CrackSubstitution() best = %q, want roughly %q", candidates[0].Plaintext, crackPlaintext)
+	}
+
+	decrypted, err := NewSubstitution(candidates[0].Key)
+	if err != nil {
+		t.Fatalf("NewSubstitution(%q) error = %v", candidates[0].Key, err)
+	}
+	if plaintext, _ := decrypted.Decrypt(ciphertext); plaintext != candidates[0].Plaintext {
+		t.Errorf("Decrypt() with recovered key = %q, want %q", plaintext, candidates[0].Plaintext)
+	}
+}
+
+func TestCrackSubstitution_Fitness(t *testing.T) {
+	s, _ := NewSubstitution("zebras")
+	ciphertext, _ := s.Encrypt(crackPlaintext)
+
+	// A fitness function that isn't the default makes the cracker refine its keys by climbing with it.
+	calls := 0
+	fitness := func(text string) float64 {
+		calls++
+		return BigramFitness(text)
+	}
+
+	candidates, err := CrackSubstitution(context.Background(), ciphertext, CrackOptions{Fitness: fitness, Results: 1, Restarts: 5})
+	if err != nil || len(candidates) != 1 {
+		t.Fatalf("CrackSubstitution() = %v, %v, want one candidate", candidates, err)
+	}
+
+	if calls < 26*25/2 {
+		t.Errorf("CrackSubstitution() called the fitness function %d times, want at least one full climb", calls)
+	}
+
+	if candidates[0].Score != BigramFitness(candidates[0].Plaintext) {
+		t.Errorf("CrackSubstitution() score = %f, want the fitness of the plaintext", candidates[0].Score)
+	}
+}
+
+func TestCrackSubstitution_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := CrackSubstitution(ctx, crackPlaintext, CrackOptions{}); err == nil {
+		t.Errorf("CrackSubstitution() returned no error for a cancelled context")
+	}
+}
@@ -0,0 +1,101 @@
+package cipher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Playfair is the digraph cipher that replaces pairs of letters according to their positions in a 5x5 square mixed
+// using a keyword. I and J share a cell.
+type Playfair struct {
+	square *square
+}
+
+// NewPlayfair creates a new Playfair cipher with the given key.
+func NewPlayfair(key string) (*Playfair, error) {
+	if strings.IndexFunc(key, unicode.IsLetter) == -1 {
+		return nil, fmt.Errorf("a key containing letters is required")
+	}
+	return &Playfair{square: newSquare(5, key)}, nil
+}
+
+// Encrypt splits the letters of the plaintext into pairs, inserting an X between doubled letters (or a Q between
+// doubled Xs) and padding the end in the same way, then replaces each pair. Characters other than letters are
+// dropped, and the ciphertext is returned in uppercase.
+func (p *Playfair) Encrypt(plaintext string) (string, error) {
+	letters := p.letters(plaintext)
+
+	var pairs []rune
+	for i := 0; i < len(letters); {
+		first := letters[i]
+		if i+1 < len(letters) && letters[i+1] != first {
+			pairs = append(pairs, first, letters[i+1])
+			i += 2
+		} else {
+			pairs = append(pairs, first, padding(first))
+			i++
+		}
+	}
+
+	return p.transform(pairs, 1), nil
+}
+
+// Decrypt reverses Encrypt, returning the plaintext in lowercase. Padding letters are not removed, as they can't be
+// distinguished from genuine ones. An error is returned if the ciphertext doesn't consist of valid pairs.
+func (p *Playfair) Decrypt(ciphertext string) (string, error) {
+	letters := p.letters(ciphertext)
+	if len(letters)%2 != 0 {
+		return "", fmt.Errorf("ciphertext must contain an even number of letters")
+	}
+
+	for i := 0; i < len(letters); i += 2 {
+		if letters[i] == letters[i+1] {
+			return "", fmt.Errorf("ciphertext contains a doubled letter: %c%c", letters[i], letters[i+1])
+		}
+	}
+
+	return strings.ToLower(p.transform(letters, -1)), nil
+}
+
+// letters returns the letters of the input as they appear in the square.
+func (p *Playfair) letters(input string) []rune {
+	var letters []rune
+	for _, r := range input {
+		if r, ok := p.square.normalise(r); ok {
+			letters = append(letters, r)
+		}
+	}
+	return letters
+}
+
+// transform replaces each pair of letters, moving in the given direction (1 to encrypt, -1 to decrypt) when both
+// letters share a row or column.
+func (p *Playfair) transform(pairs []rune, direction int) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(pairs); i += 2 {
+		row1, column1, _ := p.square.position(pairs[i])
+		row2, column2, _ := p.square.position(pairs[i+1])
+
+		switch {
+		case row1 == row2:
+			b.WriteRune(p.square.at(row1, column1+direction))
+			b.WriteRune(p.square.at(row2, column2+direction))
+		case column1 == column2:
+			b.WriteRune(p.square.at(row1+direction, column1))
+			b.WriteRune(p.square.at(row2+direction, column2))
+		default:
+			b.WriteRune(p.square.at(row1, column2))
+			b.WriteRune(p.square.at(row2, column1))
+		}
+	}
+	return b.String()
+}
+
+// padding returns the letter used to separate or pad the given letter.
+func padding(letter rune) rune {
+	if letter == 'X' {
+		return 'Q'
+	}
+	return 'X'
+}
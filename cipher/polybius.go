@@ -0,0 +1,113 @@
+package cipher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// square is a Polybius square: a grid of characters, each identified by its row and column.
+type square struct {
+	size  int
+	chars []rune
+}
+
+// newSquare creates a square of the given size, filled with the characters of the key (ignoring repeats) followed
+// by the rest of the alphabet. A 5x5 square contains the letters, with I and J sharing a cell; a 6x6 square contains
+// the letters and digits.
+func newSquare(size int, key string) *square {
+	s := &square{size: size}
+
+	alphabet := "ABCDEFGHIKLMNOPQRSTUVWXYZ"
+	if size == 6 {
+		alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	}
+
+	for _, r := range key + alphabet {
+		if r, ok := s.normalise(r); ok && !strings.ContainsRune(string(s.chars), r) {
+			s.chars = append(s.chars, r)
+		}
+	}
+	return s
+}
+
+// normalise converts the character to the form stored in the square, returning false if it can't be represented.
+func (s *square) normalise(r rune) (rune, bool) {
+	r = unicode.ToUpper(r)
+	switch {
+	case r == 'J' && s.size == 5:
+		return 'I', true
+	case r >= 'A' && r <= 'Z':
+		return r, true
+	case r >= '0' && r <= '9' && s.size == 6:
+		return r, true
+	default:
+		return 0, false
+	}
+}
+
+// position returns the row and column (from 0) of the character, returning false if it can't be represented.
+func (s *square) position(r rune) (int, int, bool) {
+	r, ok := s.normalise(r)
+	if !ok {
+		return 0, 0, false
+	}
+
+	i := strings.IndexRune(string(s.chars), r)
+	return i / s.size, i % s.size, true
+}
+
+// at returns the character at the given row and column, wrapping around the edges of the square.
+func (s *square) at(row, column int) rune {
+	row = ((row % s.size) + s.size) % s.size
+	column = ((column % s.size) + s.size) % s.size
+	return s.chars[row*s.size+column]
+}
+
+// Polybius is the cipher that replaces each letter with its row and column in a 5x5 square of letters, optionally
+// mixed using a keyword. I and J share a cell.
+type Polybius struct {
+	square *square
+}
+
+// NewPolybius creates a new Polybius square cipher. If the key is empty, the square contains the alphabet in order.
+func NewPolybius(key string) (*Polybius, error) {
+	return &Polybius{square: newSquare(5, key)}, nil
+}
+
+// Encrypt replaces each letter with its row and column (numbered from 1), separating each pair of digits with a
+// space. Characters other than letters are dropped.
+func (p *Polybius) Encrypt(plaintext string) (string, error) {
+	var pairs []string
+	for _, r := range plaintext {
+		if row, column, ok := p.square.position(r); ok {
+			pairs = append(pairs, fmt.Sprintf("%d%d", row+1, column+1))
+		}
+	}
+	return strings.Join(pairs, " "), nil
+}
+
+// Decrypt reads pairs of digits as the row and column of each letter, ignoring any other characters, and returns
+// the letters in lowercase.
+func (p *Polybius) Decrypt(ciphertext string) (string, error) {
+	var digits []int
+	for _, r := range ciphertext {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+
+	if len(digits)%2 != 0 {
+		return "", fmt.Errorf("ciphertext must contain an even number of digits")
+	}
+
+	b := &strings.Builder{}
+	for i := 0; i < len(digits); i += 2 {
+		row, column := digits[i]-1, digits[i+1]-1
+		if row < 0 || row >= p.square.size || column < 0 || column >= p.square.size {
+			return "", fmt.Errorf("invalid square position: %d%d", digits[i], digits[i+1])
+		}
+		b.WriteRune(unicode.ToLower(p.square.at(row, column)))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,161 @@
+package cipher
+
+import "fmt"
+
+// Atbash is the cipher that reverses the alphabet, so a becomes z and z becomes a. It is its own inverse.
+type Atbash struct{}
+
+// NewAtbash creates a new Atbash cipher.
+func NewAtbash() *Atbash {
+	return &Atbash{}
+}
+
+// Encrypt replaces each letter with its mirror in the alphabet, keeping its case. Other characters are unchanged.
+func (a *Atbash) Encrypt(plaintext string) (string, error) {
+	return substitute(plaintext, func(index, _ int) int {
+		return 25 - index
+	}), nil
+}
+
+// Decrypt is the same as Encrypt.
+func (a *Atbash) Decrypt(ciphertext string) (string, error) {
+	return a.Encrypt(ciphertext)
+}
+
+// Affine is the cipher that maps each letter x (numbered from 0) to (ax + b) mod 26.
+type Affine struct {
+	a, b, inverse int
+}
+
+// NewAffine creates a new Affine cipher with the given multiplier and offset. The multiplier must be coprime with 26
+// so that the cipher can be reversed.
+func NewAffine(a, b int) (*Affine, error) {
+	a = ((a % 26) + 26) % 26
+	for inverse := 1; inverse < 26; inverse++ {
+		if a*inverse%26 == 1 {
+			return &Affine{a: a, b: b, inverse: inverse}, nil
+		}
+	}
+	return nil, fmt.Errorf("multiplier %d is not coprime with 26", a)
+}
+
+// Encrypt replaces each letter using the affine function, keeping its case. Other characters are unchanged.
+func (a *Affine) Encrypt(plaintext string) (string, error) {
+	return substitute(plaintext, func(index, _ int) int {
+		return a.a*index + a.b
+	}), nil
+}
+
+// Decrypt reverses Encrypt.
+func (a *Affine) Decrypt(ciphertext string) (string, error) {
+	return substitute(ciphertext, func(index, _ int) int {
+		return a.inverse * (index - a.b)
+	}), nil
+}
+
+// Vigenere is the polyalphabetic cipher that shifts each letter forward by the corresponding letter of a repeating
+// key.
+type Vigenere struct {
+	shifts []int
+}
+
+// NewVigenere creates a new Vigenère cipher with the given key. Characters other than letters in the key are
+// ignored.
+func NewVigenere(key string) (*Vigenere, error) {
+	shifts, err := keyShifts(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Vigenere{shifts: shifts}, nil
+}
+
+// Encrypt shifts each letter forward by the next letter of the key, keeping its case. Other characters are
+// unchanged, and don't consume the key.
+func (v *Vigenere) Encrypt(plaintext string) (string, error) {
+	return substitute(plaintext, func(index, count int) int {
+		return index + v.shifts[count%len(v.shifts)]
+	}), nil
+}
+
+// Decrypt reverses Encrypt.
+func (v *Vigenere) Decrypt(ciphertext string) (string, error) {
+	return substitute(ciphertext, func(index, count int) int {
+		return index - v.shifts[count%len(v.shifts)]
+	}), nil
+}
+
+// Beaufort is the variant of the Vigenère cipher that replaces each letter by subtracting it from the corresponding
+// letter of a repeating key. It is its own inverse.
+type Beaufort struct {
+	shifts []int
+}
+
+// NewBeaufort creates a new Beaufort cipher with the given key. Characters other than letters in the key are
+// ignored.
+func NewBeaufort(key string) (*Beaufort, error) {
+	shifts, err := keyShifts(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Beaufort{shifts: shifts}, nil
+}
+
+// Encrypt subtracts each letter from the next letter of the key, keeping its case. Other characters are unchanged,
+// and don't consume the key.
+func (b *Beaufort) Encrypt(plaintext string) (string, error) {
+	return substitute(plaintext, func(index, count int) int {
+		return b.shifts[count%len(b.shifts)] - index
+	}), nil
+}
+
+// Decrypt is the same as Encrypt.
+func (b *Beaufort) Decrypt(ciphertext string) (string, error) {
+	return b.Encrypt(ciphertext)
+}
+
+// Substitution is the monoalphabetic cipher that replaces each letter with the corresponding letter of a mixed
+// alphabet.
+type Substitution struct {
+	encrypt, decrypt [26]int
+}
+
+// NewSubstitution creates a new simple substitution cipher. The mixed alphabet is made from the letters of the key
+// with duplicates removed, followed by the rest of the alphabet in order, so the key "zebras" gives
+// "zebrascdfghijklmnopqtuvwxy". A full 26-letter key is used as-is.
+func NewSubstitution(key string) (*Substitution, error) {
+	shifts, err := keyShifts(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Substitution{}
+	used := make([]bool, 26)
+	count := 0
+	for index := range 26 {
+		shifts = append(shifts, index)
+	}
+	for _, index := range shifts {
+		if !used[index] {
+			used[index] = true
+			s.encrypt[count] = index
+			s.decrypt[index] = count
+			count++
+		}
+	}
+	return s, nil
+}
+
+// Encrypt replaces each letter with the corresponding letter of the mixed alphabet, keeping its case. Other
+// characters are unchanged.
+func (s *Substitution) Encrypt(plaintext string) (string, error) {
+	return substitute(plaintext, func(index, _ int) int {
+		return s.encrypt[index]
+	}), nil
+}
+
+// Decrypt reverses Encrypt.
+func (s *Substitution) Decrypt(ciphertext string) (string, error) {
+	return substitute(ciphertext, func(index, _ int) int {
+		return s.decrypt[index]
+	}), nil
+}
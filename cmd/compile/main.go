@@ -7,18 +7,30 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 var (
-	inFile  = flag.String("in", "-", "File to read words from, or '-' for stdin")
-	outFile = flag.String("out", "words.wl", "File to write compiled spell checker to")
+	inFile       = flag.String("in", "-", "File to read words from, or '-' for stdin")
+	outFile      = flag.String("out", "words.wl", "File to write compiled spell checker to")
+	exact        = flag.Bool("exact", false, "Compile an exact word list instead of a probabilistic spell checker")
+	fpRate       = flag.Float64("fp-rate", 0.001, "Target false positive rate for valid words")
+	prefixFpRate = flag.Float64("prefix-fp-rate", 0.1, "Target false positive rate for word prefixes")
+	prefixes     = flag.Float64("prefixes-per-word", 10, "Estimated number of prefixes per word, used to size the prefix filter")
+	exactCount   = flag.Bool("count", false, "Count unique words and prefixes exactly instead of estimating from the number of lines")
+	samples      = flag.Int("samples", 100000, "Number of random non-words to test when measuring the false positive rate")
 )
 
 func main() {
 	flag.Parse()
 
-	var input io.Reader
+	var (
+		input io.Reader
+		opts  []kowalski.ModelOption
+	)
 	if *inFile == "-" {
 		input = os.Stdin
 	} else {
@@ -28,6 +40,7 @@ func main() {
 		}
 		defer f.Close()
 		input = f
+		opts = append(opts, kowalski.WithSource(filepath.Base(*inFile)))
 	}
 
 	b, err := ioutil.ReadAll(input)
@@ -38,21 +51,105 @@ func main() {
 	count := bytes.Count(b, []byte{'\n'})
 	reader := bytes.NewReader(b)
 
-	checker, err := kowalski.CreateSpellChecker(reader, count)
-	if err != nil {
-		log.Fatalf("Unable to create checker: %v", err)
-	}
-
 	out, err := os.Create(*outFile)
 	if err != nil {
 		log.Fatalf("Unable to open output: %v", err)
 	}
 	defer out.Close()
 
+	if *exact {
+		list, err := kowalski.CreateWordList(reader, opts...)
+		if err != nil {
+			log.Fatalf("Unable to create word list: %v", err)
+		}
+
+		err = kowalski.SaveWordList(out, list)
+		if err != nil {
+			log.Fatalf("Unable to save word list: %v", err)
+		}
+
+		log.Printf("Word list with %d words successfully saved to %s", list.Info().WordCount, *outFile)
+		return
+	}
+
+	opts = append(
+		opts,
+		kowalski.WithFalsePositiveRate(*fpRate),
+		kowalski.WithPrefixFalsePositiveRate(*prefixFpRate),
+		kowalski.WithPrefixesPerWord(*prefixes),
+	)
+	if *exactCount {
+		opts = append(opts, kowalski.CountUniqueWords)
+	}
+
+	checker, err := kowalski.CreateSpellChecker(reader, count, opts...)
+	if err != nil {
+		log.Fatalf("Unable to create checker: %v", err)
+	}
+
 	err = kowalski.SaveSpellChecker(out, checker)
 	if err != nil {
 		log.Fatalf("Unable to save checker: %v", err)
 	}
 
-	log.Printf("Spell checker with ~%d words successfully saved to %s", count, *outFile)
+	log.Printf("Spell checker with %d words successfully saved to %s", checker.Info().WordCount, *outFile)
+
+	if *samples > 0 {
+		valid, prefix := measureFalsePositives(checker, b, *samples)
+		log.Printf("Measured false positive rate for words: %.5f (target %g)", valid, *fpRate)
+		log.Printf("Measured false positive rate for prefixes: %.5f (target %g)", prefix, *prefixFpRate)
+	}
+}
+
+// measureFalsePositives generates random strings that are not in the word list (or prefixes of words in the list),
+// and returns the proportion that the checker incorrectly accepts as valid words and prefixes respectively. The
+// random strings have the same length distribution as the words in the list.
+func measureFalsePositives(checker *kowalski.SpellChecker, input []byte, samples int) (float64, float64) {
+	words := make(map[string]bool)
+	prefixes := make(map[string]bool)
+	var lengths []int
+	for _, line := range strings.Split(string(input), "\n") {
+		word, _ := kowalski.ParseWord(line)
+		if word == "" || words[word] {
+			continue
+		}
+
+		words[word] = true
+		lengths = append(lengths, len(word))
+		for i := range word {
+			prefixes[word[:i+1]] = true
+		}
+	}
+
+	if len(lengths) == 0 {
+		return 0, 0
+	}
+
+	randomString := func() string {
+		b := make([]byte, lengths[rand.Intn(len(lengths))])
+		for i := range b {
+			b[i] = byte('a' + rand.Intn(26))
+		}
+		return string(b)
+	}
+
+	validErrors, validTotal := 0, 0
+	prefixErrors, prefixTotal := 0, 0
+	for i := 0; i < samples; i++ {
+		if s := randomString(); !words[s] {
+			validTotal++
+			if checker.Valid(s) {
+				validErrors++
+			}
+		}
+
+		if s := randomString(); !prefixes[s] {
+			prefixTotal++
+			if checker.Prefix(s) {
+				prefixErrors++
+			}
+		}
+	}
+
+	return float64(validErrors) / float64(max(validTotal, 1)), float64(prefixErrors) / float64(max(prefixTotal, 1))
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/csmith/cryptography"
 	"github.com/csmith/kowalski/v6"
+	"github.com/csmith/kowalski/v6/cipher"
 )
 
 type Replier interface {
@@ -43,36 +45,68 @@ func addCommand[T any](target map[string]T, c T, helpText string, names ...strin
 
 func Anagram(input string, r Replier) {
 	input = strings.ToLower(input)
-	if isValidWord(input) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
 
-		words, err := kowalski.MultiplexAnagram(ctx, checkers, input, kowalski.Dedupe)
-		if err != nil {
-			r.reply("Error: %v", err)
-		} else {
-			r.reply("Anagrams for %s: %v", input, merge(words))
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexAnagram(ctx, checkers, input, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
 	} else {
-		r.reply("Invalid word: %s", input)
+		r.reply("Anagrams for %s: %v", input, merge(words))
 	}
 }
 
 func init() {
-	addCommand(textCommands, Anagram, "Attempts to find single-word anagrams, expanding '\\*' and '?' wildcards", "anagram")
+	addCommand(textCommands, Anagram, "Attempts to find single-word anagrams, expanding '?' wildcards. Letters can be added and removed with + and -, e.g. listen + ? - t, and with=word requires the answer to contain a word", "anagram")
 }
 
 func Analysis(input string, r Replier) {
-	input = strings.ToLower(input)
-	res := kowalski.Analyse(checkers[0], input)
-	if len(res) == 0 {
+	input, options, err := kowalski.ParseAnalysisOptions(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := kowalski.Analyse(ctx, checkers[0], strings.TrimSpace(input), append(options, kowalski.WithScorer(scorer))...)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	var lines []string
+	for i := range res {
+		lines = append(lines, res[i].Message)
+	}
+	if err != nil {
+		lines = append(lines, "_Some analysers didn't finish in time_")
+	}
+
+	if len(lines) == 0 {
 		r.reply("Analysis: nothing interesting found")
+		return
 	}
-	r.reply("Analysis:\n- %s", strings.Join(res, "\n- "))
+	r.reply("Analysis:\n- %s", strings.Join(lines, "\n- "))
 }
 
 func init() {
-	addCommand(textCommands, Analysis, "Analyses text and provides a summary of potentially interesting findings", "analysis", "analyze", "analyse")
+	addCommand(textCommands, Analysis, "Analyses text and provides a summary of potentially interesting findings. Analysers can be chosen by name or tag with only=a,b and skip=a,b before the text; see analysers for a list", "analysis", "analyze", "analyse")
+}
+
+func Analysers(_ string, r Replier) {
+	message := strings.Builder{}
+	message.WriteString("Analysers:")
+	for _, analyser := range kowalski.Analysers() {
+		message.WriteString(fmt.Sprintf("\n- **%s** (%s)", analyser.Name(), strings.Join(analyser.Tags(), ", ")))
+	}
+	r.reply(message.String())
+}
+
+func init() {
+	addCommand(textCommands, Analysers, "Lists the analysers used by analysis, and the tags that select them", "analysers", "analyzers")
 }
 
 func Chunk(input string, r Replier) {
@@ -136,6 +170,139 @@ func init() {
 	addCommand(fileCommands, Colours, "Counts the colours within the image", "colours", "colors")
 }
 
+func Crack(input string, r Replier) {
+	cracker, text, err := cipher.ParseCracker(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	options := cipher.CrackOptions{}
+	if ngrams != nil {
+		options.Fitness = ngrams.Fitness
+	}
+
+	res, err := cracker(ctx, text, options)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	if len(res) == 0 {
+		r.reply("Unable to crack %s", text)
+		return
+	}
+
+	out := strings.Builder{}
+	out.WriteString("Possible decryptions:\n")
+	for i, c := range res {
+		plaintext := c.Plaintext
+		if i == 0 {
+			plaintext = fmt.Sprintf("**%s**", plaintext)
+		}
+		out.WriteString(fmt.Sprintf("\tKey %s: %s (%.3f)\n", c.Key, plaintext, c.Score))
+	}
+	r.reply(out.String())
+}
+
+func init() {
+	addCommand(textCommands, Crack, "Attempts to decrypt a vigenere or substitution ciphertext without the key, showing the most likely keys. Works best on longer texts. For example: crack vigenere lxfopvefrnhr...", "crack")
+}
+
+func Decode(input string, r Replier) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := kowalski.AutoDecode(ctx, checkers[0], strings.TrimSpace(input), kowalski.AutoDecodeOptions{Scorer: scorer})
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	if len(res) == 0 {
+		r.reply("Unable to decode %s", input)
+		return
+	}
+
+	out := strings.Builder{}
+	out.WriteString("Possible decodings:\n")
+	for i, chain := range res {
+		if i == 0 {
+			out.WriteString(fmt.Sprintf("\t**%s** (%.3f)\n", chain, chain.Score()))
+		} else {
+			out.WriteString(fmt.Sprintf("\t%s (%.3f)\n", chain, chain.Score()))
+		}
+	}
+	r.reply(out.String())
+}
+
+func init() {
+	addCommand(textCommands, Decode, "Repeatedly tries decoding text as base64, base32, hex, binary, octal, decimal, A1Z26, ROT-n, reversed, morse, T9 and run-length encoding, showing the decodings that look most like English", "decode", "autodecode")
+}
+
+func Decrypt(input string, r Replier) {
+	c, text, err := cipher.Parse(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	res, err := c.Decrypt(text)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Decrypted: %s", res)
+	}
+}
+
+func init() {
+	addCommand(textCommands, Decrypt, "Decrypts text with a classical cipher. Accepts the same ciphers and options as encrypt", "decrypt", "decipher")
+}
+
+func Encrypt(input string, r Replier) {
+	c, text, err := cipher.Parse(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	res, err := c.Encrypt(text)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Encrypted: %s", res)
+	}
+}
+
+func init() {
+	addCommand(textCommands, Encrypt, "Encrypts text with a classical cipher: atbash, affine (a=5 b=8), vigenere or beaufort (key=lemon), playfair, polybius or substitution (key=...), adfgx or adfgvx (square=... key=...). For example: encrypt vigenere key=lemon attack at dawn", "encrypt", "encipher")
+}
+
+func Fuzzy(input string, r Replier) {
+	input, distance, err := kowalski.ParseFuzzyOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexFuzzy(ctx, checkers, input, distance, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Fuzzy matches for %s: %s", input, strings.Join(merge(words), ", "))
+	}
+}
+
+func init() {
+	addCommand(textCommands, Fuzzy, "Finds words within an edit distance of the input, allowing letters to be inserted, deleted, changed or swapped. Accepts distance=2 (default 1)", "fuzzy", "typo")
+}
+
 func HiddenPixels(_ string, urls []string, r Replier) {
 	res, err := http.Get(urls[0])
 	if err != nil {
@@ -163,6 +330,53 @@ func init() {
 	addCommand(fileCommands, HiddenPixels, "Finds hidden pixels in images", "hidden", "hiddenpixels")
 }
 
+func Keyboard(input string, r Replier) {
+	res := kowalski.DecodeKeyboard(checkers[0], input)
+	out := strings.Builder{}
+	out.WriteString("Keyboard decodings:\n")
+	for _, d := range res[:min(len(res), 5)] {
+		text := d.Text
+		if d.Score > 0.5 {
+			text = fmt.Sprintf("**%s**", text)
+		}
+		out.WriteString(fmt.Sprintf("\t%s: %s (%.5f)\n", d.Method, text, d.Score))
+	}
+	r.reply(out.String())
+}
+
+func init() {
+	addCommand(textCommands, Keyboard, "Decodes text typed with keys shifted left, right, up or down, remapped between QWERTY, Dvorak and AZERTY, or given as keypad coordinates such as 2,3, showing the most plausible results", "keyboard", "keys")
+}
+
+func Ladder(input string, r Replier) {
+	from, to, options, err := kowalski.ParseLadderOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ladders, err := kowalski.MultiplexWordLadder(ctx, checkers, from, to, options)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	for i := range ladders {
+		if ladders[i] != nil {
+			r.reply("Word ladder from %s to %s: %s", from, to, strings.Join(ladders[i], " → "))
+			return
+		}
+	}
+	r.reply("No word ladder found from %s to %s", from, to)
+}
+
+func init() {
+	addCommand(textCommands, Ladder, "Finds the shortest word ladder between two words, changing one letter at a time. Accepts options steps=10 and insertdelete=true", "ladder", "wordladder")
+}
+
 func Letters(input string, r Replier) {
 	res := cryptography.LetterDistribution([]byte(input))
 	max := 0
@@ -194,75 +408,181 @@ func init() {
 }
 
 func Match(input string, r Replier) {
-	input = strings.ToLower(input)
-	if isValidWord(input) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
 
-		words, err := kowalski.MultiplexMatch(ctx, checkers, input, kowalski.Dedupe)
-		if err != nil {
-			r.reply("Error: %v", err)
-		} else {
-			r.reply("Matches for %s: %v", input, merge(words))
-		}
+	if err := validateMatchOptions(options); err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	if input == "" {
+		input = options.Enumeration.Pattern()
+	}
+
+	if err := validatePattern(input); err != nil {
+		r.reply("Invalid pattern: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var words [][]string
+	if options.Enumeration.Words() > 0 {
+		words, err = kowalski.MultiplexMultiMatch(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
 	} else {
-		r.reply("Invalid word: %s", input)
+		words, err = kowalski.MultiplexMatch(ctx, checkers, input, kowalski.Ranked, kowalski.Dedupe)
+	}
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Matches for %s: %v", input, merge(words))
 	}
 }
 
 func init() {
-	addCommand(textCommands, Match, "Attempts to expand '?' wildcards to find a single-word match", "match")
+	addCommand(textCommands, Match, "Attempts to find single-word matches for a pattern using '?' (any letter), '*' (any letters), '#' (consonant), '@' (vowel), '[abc]' and '[^abc]'. An enumeration such as (3,4) fixes the words' lengths", "match")
 }
 
 func Morse(input string, r Replier) {
-	res := merge(kowalski.MultiplexFromMorse(checkers, input, kowalski.Dedupe))
-	r.reply("Matches for %s: %v", input, res)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	groups, err := kowalski.MultiplexFromMorseVariants(ctx, checkers, input, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	if len(groups) == 0 {
+		r.reply("No matches found for %s", input)
+		return
+	}
+
+	var lines []string
+	for _, group := range groups {
+		lines = append(lines, fmt.Sprintf("%s: %s", group.Mapping.Description, strings.Join(merge(group.Words), ", ")))
+	}
+	r.reply("Matches for %s:\n%s", input, strings.Join(lines, "\n"))
 }
 
 func init() {
-	addCommand(textCommands, Morse, "Attempts to split a morse code input to spell a single word", "morse")
+	addCommand(textCommands, Morse, "Decodes morse code into words, trying each plausible mapping of its symbols. Spaces separate letters and / separates words; runs of signals without spaces can spell whole words. Swapped dots and dashes, other pairs of symbols such as 0 and 1, and short and long words are tried automatically", "morse")
 }
 
-func MultiAnagram(input string, r Replier) {
-	input = strings.ToLower(input)
-	if isValidWord(input) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+func MultiMorse(input string, r Replier) {
+	input, options, err := kowalski.ParseMorseOptions(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
 
-		words, err := kowalski.MultiplexMultiAnagram(ctx, checkers, input, kowalski.Dedupe)
-		if err != nil {
-			r.reply("Error: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexMultiFromMorse(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Multi-word matches for %s: %s", input, strings.Join(merge(words), ", "))
+	}
+}
+
+func init() {
+	addCommand(textCommands, MultiMorse, "Attempts to split a morse code input into multiple words. Accepts the same options as multigram", "multimorse")
+}
+
+func ToMorse(input string, r Replier) {
+	res, err := kowalski.ToMorse(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("Morse: %s", res)
+	}
+}
+
+func init() {
+	addCommand(textCommands, ToMorse, "Encodes text as morse code", "tomorse")
+}
+
+func Models(_ string, r Replier) {
+	names := []string{"**Primary**", "_Backup_"}
+
+	message := strings.Builder{}
+	message.WriteString("Dictionaries:")
+	for i := range checkers {
+		message.WriteString(fmt.Sprintf("\n- %s: ", names[i]))
+		if model, ok := checkers[i].(kowalski.Model); ok {
+			message.WriteString(model.Info().String())
 		} else {
-			r.reply("Multi anagrams for %s: %v", input, strings.Join(merge(words), ", "))
+			message.WriteString("no information available")
 		}
+	}
+	if ngrams != nil {
+		message.WriteString(fmt.Sprintf("\nScoring: %s", ngrams.Info()))
+	}
+	r.reply(message.String())
+}
+
+func init() {
+	addCommand(textCommands, Models, "Shows information about the dictionaries used to find words", "models", "dictionaries")
+}
+
+func MultiAnagram(input string, r Replier) {
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexMultiAnagram(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
 	} else {
-		r.reply("Invalid word: %s", input)
+		r.reply("Multi anagrams for %s: %v", input, strings.Join(merge(words), ", "))
 	}
 }
 
 func init() {
-	addCommand(textCommands, MultiAnagram, "Attempts to find multi-word anagrams, expanding '?' wildcards", "multigram", "multianagram")
+	addCommand(textCommands, MultiAnagram, "Attempts to find multi-word anagrams, expanding '?' wildcards. Letters can be added and removed with + and -. Accepts options such as min=3, max=8, words=2, minwords=2, maxwords=3, with=cat and enumerations such as (3,4)", "multigram", "multianagram")
 }
 
 func MultiMatch(input string, r Replier) {
-	input = strings.ToLower(input)
-	if isValidWord(input) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
 
-		words, err := kowalski.MultiplexMultiMatch(ctx, checkers, input, kowalski.Dedupe)
-		if err != nil {
-			r.reply("Error: %v", err)
-		} else {
-			r.reply("Multi matches for %s: %s", input, strings.Join(merge(words), ", "))
-		}
+	if input == "" {
+		input = options.Enumeration.Pattern()
+	}
+
+	if err := validatePattern(input); err != nil {
+		r.reply("Invalid pattern: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexMultiMatch(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
 	} else {
-		r.reply("Invalid word: %s", input)
+		r.reply("Multi matches for %s: %s", input, strings.Join(merge(words), ", "))
 	}
 }
 
 func init() {
-	addCommand(textCommands, MultiMatch, "Attempts to expand '?' wildcards to find multi-word matches", "multimatch")
+	addCommand(textCommands, MultiMatch, "Attempts to find multi-word matches for a pattern, using the same syntax as match. Accepts the same options as multigram", "multimatch")
 }
 
 func OffByOne(input string, r Replier) {
@@ -271,7 +591,7 @@ func OffByOne(input string, r Replier) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		words, err := kowalski.MultiplexOffByOne(ctx, checkers, input, kowalski.Dedupe)
+		words, err := kowalski.MultiplexOffByOne(ctx, checkers, input, kowalski.Ranked, kowalski.Dedupe)
 		if err != nil {
 			r.reply("Error: %v", err)
 		} else {
@@ -328,7 +648,7 @@ func Shift(input string, r Replier) {
 	out := strings.Builder{}
 	out.WriteString("Caesar shifts:\n")
 	for i, s := range res {
-		score := kowalski.Score(checkers[0], string(s))
+		score := scorer.Score(string(s))
 		if score > 0.5 {
 			s = []byte(fmt.Sprintf("**%s**", s))
 		}
@@ -341,17 +661,77 @@ func init() {
 	addCommand(textCommands, Shift, "Shows the result of the 25 possible caesar shifts", "shift", "caesar")
 }
 
+func SubAnagram(input string, r Replier) {
+	input, options, err := kowalski.ParseSubAnagramOptions(strings.ToLower(input))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexSubAnagram(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	var groups []string
+	for _, group := range mergeByLength(words) {
+		groups = append(groups, fmt.Sprintf("%d: %s", group.Length, strings.Join(group.Words, ", ")))
+	}
+	r.reply("Sub-anagrams for %s:\n%s", input, strings.Join(groups, "\n"))
+}
+
+func init() {
+	addCommand(textCommands, SubAnagram, "Finds words using some of the given letters, expanding '?' wildcards. Letters can be added and removed with + and -. Accepts options such as min=5 and using=x (letters every word must use), and with=word requires words to contain a word", "subgram", "subanagram")
+}
+
 func T9(input string, r Replier) {
-	if isValidT9(input) {
-		res := merge(kowalski.MultiplexFromT9(checkers, input, kowalski.Dedupe))
-		r.reply("Matches for %s: %v", input, res)
+	input, options, err := kowalski.ParseT9Options(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	if !isValidT9(input, options.Layout) {
+		r.reply("Invalid T9 input: %s", input)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexFromT9(ctx, checkers, input, options, kowalski.Ranked, kowalski.Dedupe)
+	if err != nil {
+		r.reply("Error: %v", err)
 	} else {
-		r.reply("Invalid word: %s", input)
+		r.reply("Matches for %s: %v", input, merge(words))
+	}
+}
+
+func init() {
+	addCommand(textCommands, T9, "Attempts to treat a series of numbers as T9 input to spell words. 0 or spaces separate words, and multi-tap input such as 44 444 is detected automatically. Accepts options such as layout=nokia, layout=2:abc,3:def and multitap=true", "t9")
+}
+
+func ToT9(input string, r Replier) {
+	input, options, err := kowalski.ParseT9Options(input)
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
+	res, err := kowalski.ToT9(input, options)
+	if err != nil {
+		r.reply("Error: %v", err)
+	} else {
+		r.reply("T9: %s", res)
 	}
 }
 
 func init() {
-	addCommand(textCommands, T9, "Attempts to treat a series of numbers as T9 input to spell a single word", "t9")
+	addCommand(textCommands, ToT9, "Encodes text as T9 key presses. Accepts the same options as t9", "tot9")
 }
 
 func Transpose(input string, r Replier) {
@@ -428,7 +808,16 @@ func init() {
 
 func WordSearch(input string, r Replier) {
 	input = strings.ToLower(input)
-	res := kowalski.MultiplexWordSearch(checkers, strings.Split(input, "\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := kowalski.MultiplexWordSearch(ctx, checkers, strings.Split(input, "\n"))
+	if err != nil {
+		r.reply("Error: %v", err)
+		return
+	}
+
 	r.reply(
 		"Words found:\n\nNormal: %s\n\nUD: %s",
 		strings.Join(countReps(res[0]), ", "),
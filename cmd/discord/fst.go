@@ -10,6 +10,7 @@ import (
 
 	"github.com/blevesearch/vellum"
 	"github.com/blevesearch/vellum/regexp"
+	"github.com/csmith/kowalski/v6"
 	"github.com/csmith/kowalski/v6/fst"
 )
 
@@ -72,6 +73,28 @@ func init() {
 	}
 }
 
+func FstFuzzy(input string, r Replier) {
+	input, distance, err := kowalski.ParseFuzzyOptions(input)
+	if err != nil {
+		r.reply("Error: %s", err.Error())
+		return
+	}
+
+	automaton, err := fst.NewLevenshteinAutomaton(input, distance)
+	if err != nil {
+		r.reply("Error: %s", err.Error())
+		return
+	}
+
+	fstQuery(automaton, input, r)
+}
+
+func init() {
+	if *fstModel != "" {
+		addCommand(textCommands, FstFuzzy, "Attempts to find words from wikipedia within an edit distance of the input. Accepts distance=2 (default 1)", "fstfuzzy")
+	}
+}
+
 func WordLink(input string, r Replier) {
 	input = strings.ToLower(input)
 	parts := strings.Split(input, " ")
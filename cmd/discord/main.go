@@ -4,11 +4,14 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
 	"sort"
 	"strings"
 	"syscall"
+	"unicode"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/csmith/kowalski/v6"
@@ -18,9 +21,12 @@ var (
 	token       = flag.String("token", "", "Discord bot token")
 	goodModel   = flag.String("good-model", "models/combined.wl", "Path of the 'good' model")
 	backupModel = flag.String("backup-model", "models/urbandictionary.wl", "Path of the 'backup' model")
+	ngramModel  = flag.String("ngram-model", "", "Path of an n-gram model used to score text, such as models/quadgrams.ng (if not set, text is scored using the 'good' model)")
 	prefix      = flag.String("prefix", "!", "Character(s) to require before commands")
 
-	checkers []*kowalski.SpellChecker
+	checkers []kowalski.Dictionary
+	ngrams   *kowalski.NGrams
+	scorer   kowalski.Scorer
 )
 
 func init() {
@@ -28,11 +34,17 @@ func init() {
 }
 
 func main() {
-	checkers = []*kowalski.SpellChecker{
+	checkers = []kowalski.Dictionary{
 		loadModel(*goodModel),
 		loadModel(*backupModel),
 	}
 
+	scorer = kowalski.DictionaryScorer(checkers[0])
+	if *ngramModel != "" {
+		ngrams = loadNGrams(*ngramModel)
+		scorer = ngrams
+	}
+
 	dg, err := discordgo.New(fmt.Sprintf("Bot %s", *token))
 	if err != nil {
 		fmt.Println("error creating Discord session,", err)
@@ -56,17 +68,35 @@ func main() {
 	dg.Close()
 }
 
-func loadModel(path string) (res *kowalski.SpellChecker) {
+func loadModel(path string) (res kowalski.Model) {
 	f, err := os.Open(path)
 	if err != nil {
 		log.Panicf("Failed to open model: %v", err)
 	}
 	defer f.Close()
 
-	res, err = kowalski.LoadSpellChecker(f)
+	res, err = kowalski.LoadModel(f)
 	if err != nil {
-		log.Panicf("Failed to load model: %v", err)
+		log.Panicf("Failed to load model %s: %v", path, err)
 	}
+
+	log.Printf("Loaded model %s: %s", path, res.Info())
+	return res
+}
+
+func loadNGrams(path string) *kowalski.NGrams {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Panicf("Failed to open n-gram model: %v", err)
+	}
+	defer f.Close()
+
+	res, err := kowalski.LoadNGrams(f)
+	if err != nil {
+		log.Panicf("Failed to load n-gram model %s: %v", path, err)
+	}
+
+	log.Printf("Loaded n-gram model %s: %s", path, res.Info())
 	return res
 }
 
@@ -140,18 +170,52 @@ func subtract(input, exclusions []string) []string {
 	return res
 }
 
+// merge formats the words found by each checker, emboldening those from the primary checker and italicising those from
+// the backup. Words with a frequency score are kept in ranked order and shown with their score; if no words have
+// scores, the results are sorted alphabetically.
 func merge(words [][]string) []string {
 	var res []string
+	ranked := false
 	for i := range words {
-		for j := range words[i] {
+		for _, result := range kowalski.Rank(checkers[i], words[i]) {
+			word := fmt.Sprintf("**%s**", result.Result)
 			if i > 0 {
-				res = append(res, fmt.Sprintf("_%s_", words[i][j]))
-			} else {
-				res = append(res, fmt.Sprintf("**%s**", words[i][j]))
+				word = fmt.Sprintf("_%s_", result.Result)
+			}
+
+			if result.Score > 0 {
+				ranked = true
+				word = fmt.Sprintf("%s (%.0f)", word, result.Score)
+			}
+			res = append(res, word)
+		}
+	}
+
+	if !ranked {
+		sort.Strings(res)
+	}
+	return res
+}
+
+// mergeByLength groups the words from each checker by length, longest first, formatting each group as merge does.
+func mergeByLength(words [][]string) []kowalski.LengthGroup {
+	byLength := make(map[int][][]string)
+	for i := range words {
+		for _, group := range kowalski.GroupByLength(words[i]) {
+			if byLength[group.Length] == nil {
+				byLength[group.Length] = make([][]string, len(words))
 			}
+			byLength[group.Length][i] = group.Words
 		}
 	}
-	sort.Strings(res)
+
+	lengths := slices.Sorted(maps.Keys(byLength))
+	slices.Reverse(lengths)
+
+	var res []kowalski.LengthGroup
+	for _, length := range lengths {
+		res = append(res, kowalski.LengthGroup{Length: length, Words: merge(byLength[length])})
+	}
 	return res
 }
 
@@ -184,6 +248,29 @@ func countReps(input []string) []string {
 	return res
 }
 
+// validatePattern checks that a Match pattern is non-empty and compiles.
+func validatePattern(pattern string) error {
+	if len(pattern) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+
+	_, err := kowalski.CompilePattern(pattern)
+	return err
+}
+
+// validateMatchOptions checks that the options given to the match command don't constrain words without an
+// enumeration, as single-word matches would ignore them.
+func validateMatchOptions(options kowalski.MultiWordOptions) error {
+	if options.Enumeration.Words() > 0 {
+		return nil
+	}
+
+	if options.MinWordLength > 0 || options.MaxWordLength > 0 || options.MinWords > 0 || options.MaxWords > 0 || len(options.Required) > 0 {
+		return fmt.Errorf("options require an enumeration such as (3,4); use multimatch to match multiple words")
+	}
+	return nil
+}
+
 func isValidWord(word string) bool {
 	if len(word) == 0 {
 		return false
@@ -197,13 +284,13 @@ func isValidWord(word string) bool {
 	return true
 }
 
-func isValidT9(word string) bool {
-	if len(word) == 0 {
+func isValidT9(word string, layout kowalski.T9Layout) bool {
+	if len(strings.TrimSpace(word)) == 0 {
 		return false
 	}
 
 	for _, r := range word {
-		if r < '2' || r > '9' {
+		if _, ok := layout[r]; !ok && !unicode.IsSpace(r) {
 			return false
 		}
 	}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/csmith/kowalski/v6"
+)
+
+var (
+	inFile  = flag.String("in", "-", "File containing English text to train on, or '-' for stdin")
+	outFile = flag.String("out", "quadgrams.ng", "File to write the trained n-gram model to")
+	size    = flag.Int("n", 4, "Number of letters in each n-gram")
+	source  = flag.String("source", "", "Description of the training text to record in the model (defaults to the input file name)")
+)
+
+func main() {
+	flag.Parse()
+
+	var (
+		input io.Reader
+		opts  []kowalski.ModelOption
+	)
+	if *inFile == "-" {
+		input = os.Stdin
+	} else {
+		f, err := os.Open(*inFile)
+		if err != nil {
+			log.Fatalf("Unable to open input: %v", err)
+		}
+		defer f.Close()
+		input = f
+		opts = append(opts, kowalski.WithSource(filepath.Base(*inFile)))
+	}
+
+	if *source != "" {
+		opts = append(opts, kowalski.WithSource(*source))
+	}
+
+	model, err := kowalski.CreateNGrams(input, *size, opts...)
+	if err != nil {
+		log.Fatalf("Unable to train model: %v", err)
+	}
+
+	out, err := os.Create(*outFile)
+	if err != nil {
+		log.Fatalf("Unable to open output: %v", err)
+	}
+	defer out.Close()
+
+	if err := kowalski.SaveNGrams(out, model); err != nil {
+		log.Fatalf("Unable to save model: %v", err)
+	}
+
+	log.Printf("Model saved to %s: %s", *outFile, model.Info())
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -12,35 +13,58 @@ import (
 
 	"github.com/csmith/cryptography"
 	"github.com/csmith/kowalski/v6"
+	"github.com/csmith/kowalski/v6/cipher"
 )
 
-func processAnagram(input string) (interface{}, error) {
+func processAnagram(input string, p page) (interface{}, error) {
 	input = strings.ToLower(input)
-	if !isValidWord(input) {
-		return nil, fmt.Errorf("invalid word: %s", input)
-	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	words, err := kowalski.MultiplexAnagram(ctx, checkers, input, kowalski.Dedupe)
+	words, err := kowalski.MultiplexAnagram(ctx, checkers, input, p.options(kowalski.Ranked, kowalski.Dedupe)...)
 	if err != nil {
 		return nil, err
 	}
 
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processAnalysis(input string) (interface{}, error) {
+	input, options, err := kowalski.ParseAnalysisOptions(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	input = strings.TrimSpace(input)
+	res, err := kowalski.Analyse(ctx, checkers[0], input, append(options, kowalski.WithScorer(scorer))...)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+
 	return map[string]interface{}{
-		"input":  input,
-		"result": merge(words),
+		"input":    input,
+		"result":   res,
+		"timedOut": err != nil,
 	}, nil
 }
 
-func processAnalysis(input string) (interface{}, error) {
-	input = strings.ToLower(input)
-	res := kowalski.Analyse(checkers[0], input)
+func processAnalysers() (interface{}, error) {
+	var analysers []map[string]interface{}
+	for _, analyser := range kowalski.Analysers() {
+		analysers = append(analysers, map[string]interface{}{
+			"name": analyser.Name(),
+			"tags": analyser.Tags(),
+		})
+	}
 
 	return map[string]interface{}{
-		"input":  input,
-		"result": res,
+		"analysers": analysers,
 	}, nil
 }
 
@@ -66,6 +90,136 @@ func processChunk(input string) (interface{}, error) {
 	}, nil
 }
 
+func processCrack(input string) (interface{}, error) {
+	cracker, text, err := cipher.ParseCracker(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	options := cipher.CrackOptions{}
+	if ngrams != nil {
+		options.Fitness = ngrams.Fitness
+	}
+
+	candidates, err := cracker(ctx, text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"input":      input,
+		"candidates": candidates,
+	}, nil
+}
+
+func processDecode(input string) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	input = strings.TrimSpace(input)
+	chains, err := kowalski.AutoDecode(ctx, checkers[0], input, kowalski.AutoDecodeOptions{Scorer: scorer})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"input":  input,
+		"chains": chains,
+	}, nil
+}
+
+func processDecrypt(input string) (interface{}, error) {
+	c, text, err := cipher.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.Decrypt(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"input":  input,
+		"result": result,
+	}, nil
+}
+
+func processEncrypt(input string) (interface{}, error) {
+	c, text, err := cipher.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.Encrypt(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"input":  input,
+		"result": result,
+	}, nil
+}
+
+func processFuzzy(input string, p page) (interface{}, error) {
+	input, distance, err := kowalski.ParseFuzzyOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexFuzzy(ctx, checkers, input, distance, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processKeyboard(input string) (interface{}, error) {
+	return map[string]interface{}{
+		"input":     input,
+		"decodings": kowalski.DecodeKeyboard(checkers[0], input),
+	}, nil
+}
+
+func processLadder(input string) (interface{}, error) {
+	from, to, options, err := kowalski.ParseLadderOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ladders, err := kowalski.MultiplexWordLadder(ctx, checkers, from, to, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var ladder []string
+	for i := range ladders {
+		if ladders[i] != nil {
+			ladder = ladders[i]
+			break
+		}
+	}
+
+	return map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"ladder": ladder,
+	}, nil
+}
+
 func processLetters(input string) (interface{}, error) {
 	res := cryptography.LetterDistribution([]byte(input))
 
@@ -80,76 +234,162 @@ func processLetters(input string) (interface{}, error) {
 	}, nil
 }
 
-func processMatch(input string) (interface{}, error) {
-	input = strings.ToLower(input)
-	if !isValidWord(input) {
-		return nil, fmt.Errorf("invalid word: %s", input)
+func processMatch(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMatchOptions(options); err != nil {
+		return nil, err
+	}
+
+	if input == "" {
+		input = options.Enumeration.Pattern()
+	}
+
+	if err := validatePattern(input); err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	words, err := kowalski.MultiplexMatch(ctx, checkers, input, kowalski.Dedupe)
+	var words [][]string
+	if options.Enumeration.Words() > 0 {
+		words, err = kowalski.MultiplexMultiMatch(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	} else {
+		words, err = kowalski.MultiplexMatch(ctx, checkers, input, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processMorse(input string, p page) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	groups, err := kowalski.MultiplexFromMorseVariants(ctx, checkers, input, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateMorse(map[string]interface{}{
+		"input": input,
+	}, groups, p), nil
+}
+
+func processMultiMorse(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseMorseOptions(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexMultiFromMorse(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processToMorse(input string) (interface{}, error) {
+	result, err := kowalski.ToMorse(input)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
 		"input":  input,
-		"result": merge(words),
+		"result": result,
 	}, nil
 }
 
-func processMorse(input string) (interface{}, error) {
-	res := merge(kowalski.MultiplexFromMorse(checkers, input, kowalski.Dedupe))
+func processModels() (interface{}, error) {
+	names := []string{"primary", "backup"}
+
+	var models []map[string]interface{}
+	for i := range checkers {
+		model := map[string]interface{}{
+			"name": names[i],
+		}
+		if m, ok := checkers[i].(kowalski.Model); ok {
+			model["info"] = m.Info()
+			model["description"] = m.Info().String()
+		}
+		models = append(models, model)
+	}
+
+	if ngrams != nil {
+		models = append(models, map[string]interface{}{
+			"name":        "scoring",
+			"info":        ngrams.Info(),
+			"description": ngrams.Info().String(),
+		})
+	}
 
 	return map[string]interface{}{
-		"input":  input,
-		"result": res,
+		"models": models,
 	}, nil
 }
 
-func processMultiAnagram(input string) (interface{}, error) {
-	input = strings.ToLower(input)
-	if !isValidWord(input) {
-		return nil, fmt.Errorf("invalid word: %s", input)
+func processMultiAnagram(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	words, err := kowalski.MultiplexMultiAnagram(ctx, checkers, input, kowalski.Dedupe)
+	words, err := kowalski.MultiplexMultiAnagram(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":  input,
-		"result": merge(words),
-	}, nil
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
 }
 
-func processMultiMatch(input string) (interface{}, error) {
-	input = strings.ToLower(input)
-	if !isValidWord(input) {
-		return nil, fmt.Errorf("invalid word: %s", input)
+func processMultiMatch(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseMultiWordOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
+	}
+
+	if input == "" {
+		input = options.Enumeration.Pattern()
+	}
+
+	if err := validatePattern(input); err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	words, err := kowalski.MultiplexMultiMatch(ctx, checkers, input, kowalski.Dedupe)
+	words, err := kowalski.MultiplexMultiMatch(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":  input,
-		"result": merge(words),
-	}, nil
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
 }
 
-func processOffByOne(input string) (interface{}, error) {
+func processOffByOne(input string, p page) (interface{}, error) {
 	input = strings.ToLower(input)
 	if !isValidWord(input) {
 		return nil, fmt.Errorf("invalid word: %s", input)
@@ -158,15 +398,14 @@ func processOffByOne(input string) (interface{}, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	words, err := kowalski.MultiplexOffByOne(ctx, checkers, input, kowalski.Dedupe)
+	words, err := kowalski.MultiplexOffByOne(ctx, checkers, input, p.options(kowalski.Ranked, kowalski.Dedupe)...)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":  input,
-		"result": merge(words),
-	}, nil
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
 }
 
 func processShift(input string) (interface{}, error) {
@@ -174,7 +413,7 @@ func processShift(input string) (interface{}, error) {
 
 	shifts := make([]map[string]interface{}, 0, len(res))
 	for i, s := range res {
-		score := kowalski.Score(checkers[0], string(s))
+		score := scorer.Score(string(s))
 		shifts = append(shifts, map[string]interface{}{
 			"shift": i,
 			"text":  string(s),
@@ -188,16 +427,62 @@ func processShift(input string) (interface{}, error) {
 	}, nil
 }
 
-func processT9(input string) (interface{}, error) {
-	if !isValidT9(input) {
+func processSubAnagram(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseSubAnagramOptions(strings.ToLower(input))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexSubAnagram(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateLengths(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processT9(input string, p page) (interface{}, error) {
+	input, options, err := kowalski.ParseT9Options(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isValidT9(input, options.Layout) {
 		return nil, fmt.Errorf("invalid T9 input: %s", input)
 	}
 
-	res := merge(kowalski.MultiplexFromT9(checkers, input, kowalski.Dedupe))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	words, err := kowalski.MultiplexFromT9(ctx, checkers, input, options, p.options(kowalski.Ranked, kowalski.Dedupe)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginateWords(map[string]interface{}{
+		"input": input,
+	}, words, p), nil
+}
+
+func processToT9(input string) (interface{}, error) {
+	input, options, err := kowalski.ParseT9Options(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := kowalski.ToT9(input, options)
+	if err != nil {
+		return nil, err
+	}
 
 	return map[string]interface{}{
 		"input":  input,
-		"result": res,
+		"result": result,
 	}, nil
 }
 
@@ -212,7 +497,14 @@ func processTranspose(input string) (interface{}, error) {
 
 func processWordSearch(input string) (interface{}, error) {
 	input = strings.ToLower(input)
-	res := kowalski.MultiplexWordSearch(checkers, strings.Split(input, "\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := kowalski.MultiplexWordSearch(ctx, checkers, strings.Split(input, "\n"))
+	if err != nil {
+		return nil, err
+	}
 
 	return map[string]interface{}{
 		"input":  input,
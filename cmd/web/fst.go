@@ -10,6 +10,7 @@ import (
 
 	"github.com/blevesearch/vellum"
 	vellumRegexp "github.com/blevesearch/vellum/regexp"
+	"github.com/csmith/kowalski/v6"
 	"github.com/csmith/kowalski/v6/fst"
 )
 
@@ -28,7 +29,7 @@ type fstMatch struct {
 	Score uint64 `json:"score"`
 }
 
-func processFstAnagram(input string) (interface{}, error) {
+func processFstAnagram(input string, p page) (interface{}, error) {
 	automaton, err := fst.NewAnagramAutomaton(input)
 	if err != nil {
 		return nil, err
@@ -39,13 +40,12 @@ func processFstAnagram(input string) (interface{}, error) {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":   input,
-		"matches": matches,
-	}, nil
+	return paginate(map[string]interface{}{
+		"input": input,
+	}, "matches", matches, p), nil
 }
 
-func processFstRegex(input string) (interface{}, error) {
+func processFstRegex(input string, p page) (interface{}, error) {
 	automaton, err := vellumRegexp.New(input)
 	if err != nil {
 		return nil, err
@@ -56,23 +56,42 @@ func processFstRegex(input string) (interface{}, error) {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":   input,
-		"matches": matches,
-	}, nil
+	return paginate(map[string]interface{}{
+		"input": input,
+	}, "matches", matches, p), nil
 }
 
-func processFstMorse(input string) (interface{}, error) {
+func processFstMorse(input string, p page) (interface{}, error) {
 	automaton := fst.NewMorseAutomaton(input)
 	matches, err := fstQuery(automaton)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"input":   input,
-		"matches": matches,
-	}, nil
+	return paginate(map[string]interface{}{
+		"input": input,
+	}, "matches", matches, p), nil
+}
+
+func processFstFuzzy(input string, p page) (interface{}, error) {
+	input, distance, err := kowalski.ParseFuzzyOptions(input)
+	if err != nil {
+		return nil, err
+	}
+
+	automaton, err := fst.NewLevenshteinAutomaton(input, distance)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := fstQuery(automaton)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(map[string]interface{}{
+		"input": input,
+	}, "matches", matches, p), nil
 }
 
 func processWordLink(input string) (interface{}, error) {
@@ -9,11 +9,15 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
+	"unicode"
 
 	"github.com/csmith/kowalski/v6"
 )
@@ -25,14 +29,19 @@ var (
 	port        = flag.Int("port", 8080, "HTTP port to listen on")
 	goodModel   = flag.String("good-model", "models/combined.wl", "Path of the 'good' model")
 	backupModel = flag.String("backup-model", "models/urbandictionary.wl", "Path of the 'backup' model")
+	ngramModel  = flag.String("ngram-model", "", "Path of an n-gram model used to score text, such as models/quadgrams.ng (if not set, text is scored using the 'good' model)")
 	fstModel    = flag.String("fst-model", "", "Path to FST for fast word operations")
 
-	checkers []*kowalski.SpellChecker
+	checkers []kowalski.Dictionary
+	ngrams   *kowalski.NGrams
+	scorer   kowalski.Scorer
 )
 
 type Request struct {
 	Command string `json:"command"`
 	Input   string `json:"input"`
+	Offset  int    `json:"offset,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
 }
 
 type Response struct {
@@ -46,11 +55,17 @@ func init() {
 }
 
 func main() {
-	checkers = []*kowalski.SpellChecker{
+	checkers = []kowalski.Dictionary{
 		loadModel(*goodModel),
 		loadModel(*backupModel),
 	}
 
+	scorer = kowalski.DictionaryScorer(checkers[0])
+	if *ngramModel != "" {
+		ngrams = loadNGrams(*ngramModel)
+		scorer = ngrams
+	}
+
 	if *fstModel != "" {
 		initFST(*fstModel)
 	}
@@ -92,17 +107,35 @@ func main() {
 	log.Println("Server stopped")
 }
 
-func loadModel(path string) *kowalski.SpellChecker {
+func loadModel(path string) kowalski.Model {
 	f, err := os.Open(path)
 	if err != nil {
 		log.Panicf("Failed to open model: %v", err)
 	}
 	defer f.Close()
 
-	res, err := kowalski.LoadSpellChecker(f)
+	res, err := kowalski.LoadModel(f)
 	if err != nil {
-		log.Panicf("Failed to load model: %v", err)
+		log.Panicf("Failed to load model %s: %v", path, err)
 	}
+
+	log.Printf("Loaded model %s: %s", path, res.Info())
+	return res
+}
+
+func loadNGrams(path string) *kowalski.NGrams {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Panicf("Failed to open n-gram model: %v", err)
+	}
+	defer f.Close()
+
+	res, err := kowalski.LoadNGrams(f)
+	if err != nil {
+		log.Panicf("Failed to load n-gram model %s: %v", path, err)
+	}
+
+	log.Printf("Loaded n-gram model %s: %s", path, res.Info())
 	return res
 }
 
@@ -118,7 +151,12 @@ func handleCommand(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := processCommand(req.Command, req.Input)
+	if req.Offset < 0 || req.Limit < 0 {
+		writeJSON(w, Response{Success: false, Error: "Invalid offset or limit"})
+		return
+	}
+
+	result, err := processCommand(req.Command, req.Input, page{offset: req.Offset, limit: req.Limit})
 	if err != nil {
 		writeJSON(w, Response{Success: false, Error: err.Error()})
 		return
@@ -161,30 +199,56 @@ func writeJSON(w http.ResponseWriter, v interface{}) {
 	json.NewEncoder(w).Encode(v)
 }
 
-func processCommand(command, input string) (interface{}, error) {
+func processCommand(command, input string, p page) (interface{}, error) {
 	switch command {
 	case "anagram":
-		return processAnagram(input)
+		return processAnagram(input, p)
 	case "analysis":
 		return processAnalysis(input)
+	case "analysers":
+		return processAnalysers()
 	case "chunk":
 		return processChunk(input)
+	case "crack":
+		return processCrack(input)
+	case "decode":
+		return processDecode(input)
+	case "decrypt":
+		return processDecrypt(input)
+	case "encrypt":
+		return processEncrypt(input)
+	case "fuzzy":
+		return processFuzzy(input, p)
+	case "keyboard":
+		return processKeyboard(input)
+	case "ladder":
+		return processLadder(input)
 	case "letters":
 		return processLetters(input)
 	case "match":
-		return processMatch(input)
+		return processMatch(input, p)
+	case "models":
+		return processModels()
 	case "morse":
-		return processMorse(input)
+		return processMorse(input, p)
+	case "multimorse":
+		return processMultiMorse(input, p)
 	case "multianagram":
-		return processMultiAnagram(input)
+		return processMultiAnagram(input, p)
 	case "multimatch":
-		return processMultiMatch(input)
+		return processMultiMatch(input, p)
 	case "offbyone":
-		return processOffByOne(input)
+		return processOffByOne(input, p)
 	case "shift":
 		return processShift(input)
+	case "subanagram":
+		return processSubAnagram(input, p)
 	case "t9":
-		return processT9(input)
+		return processT9(input, p)
+	case "tomorse":
+		return processToMorse(input)
+	case "tot9":
+		return processToT9(input)
 	case "transpose":
 		return processTranspose(input)
 	case "wordsearch":
@@ -197,17 +261,22 @@ func processCommand(command, input string) (interface{}, error) {
 		return processCheckWords(input)
 	case "fstanagram":
 		if fstTransducer != nil {
-			return processFstAnagram(input)
+			return processFstAnagram(input, p)
 		}
 		return nil, fmt.Errorf("FST model not loaded")
 	case "fstregex":
 		if fstTransducer != nil {
-			return processFstRegex(input)
+			return processFstRegex(input, p)
 		}
 		return nil, fmt.Errorf("FST model not loaded")
 	case "fstmorse":
 		if fstTransducer != nil {
-			return processFstMorse(input)
+			return processFstMorse(input, p)
+		}
+		return nil, fmt.Errorf("FST model not loaded")
+	case "fstfuzzy":
+		if fstTransducer != nil {
+			return processFstFuzzy(input, p)
 		}
 		return nil, fmt.Errorf("FST model not loaded")
 	case "wordlink":
@@ -233,6 +302,29 @@ func processImageCommand(command string, file io.Reader) (interface{}, error) {
 	}
 }
 
+// validatePattern checks that a Match pattern is non-empty and compiles.
+func validatePattern(pattern string) error {
+	if len(pattern) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+
+	_, err := kowalski.CompilePattern(pattern)
+	return err
+}
+
+// validateMatchOptions checks that the options given to the match command don't constrain words without an
+// enumeration, as single-word matches would ignore them.
+func validateMatchOptions(options kowalski.MultiWordOptions) error {
+	if options.Enumeration.Words() > 0 {
+		return nil
+	}
+
+	if options.MinWordLength > 0 || options.MaxWordLength > 0 || options.MinWords > 0 || options.MaxWords > 0 || len(options.Required) > 0 {
+		return fmt.Errorf("options require an enumeration such as (3,4); use multimatch to match multiple words")
+	}
+	return nil
+}
+
 func isValidWord(word string) bool {
 	if len(word) == 0 {
 		return false
@@ -246,29 +338,194 @@ func isValidWord(word string) bool {
 	return true
 }
 
-func isValidT9(word string) bool {
-	if len(word) == 0 {
+func isValidT9(word string, layout kowalski.T9Layout) bool {
+	if len(strings.TrimSpace(word)) == 0 {
 		return false
 	}
 
 	for _, r := range word {
-		if r < '2' || r > '9' {
+		if _, ok := layout[r]; !ok && !unicode.IsSpace(r) {
 			return false
 		}
 	}
 	return true
 }
 
+// page describes the subset of results a client has asked for. A limit of zero means all results should be returned.
+type page struct {
+	offset int
+	limit  int
+}
+
+// options returns the given multiplex options, plus a limit that ensures enough results are found to fill the page.
+// One extra result is requested so that we can tell if there are more results after the page.
+func (p page) options(opts ...kowalski.MultiplexOption) []kowalski.MultiplexOption {
+	if p.limit > 0 {
+		opts = append(opts, kowalski.Limit(p.offset+p.limit+1))
+	}
+	return opts
+}
+
+// bounds returns the start and end indices of the page within the given number of items.
+func (p page) bounds(total int) (int, int) {
+	start := min(p.offset, total)
+	end := total
+	if p.limit > 0 {
+		end = min(start+p.limit, total)
+	}
+	return start, end
+}
+
+// paginate stores the requested page of items in the result under the given key, along with the offset of the page,
+// the total number of items and whether any items exist after the page. If the solvers were limited, the total is
+// only a lower bound when the results are truncated.
+func paginate[T any](result map[string]interface{}, key string, items []T, p page) map[string]interface{} {
+	total := len(items)
+	start, end := p.bounds(total)
+
+	result[key] = items[start:end]
+	result["offset"] = start
+	result["total"] = total
+	result["truncated"] = end < total
+	return result
+}
+
+// paginateWords merges the words found by each checker and stores the requested page of them in the result as
+// paginate does, along with the frequency scores of the words on the page.
+func paginateWords(result map[string]interface{}, words [][]string, p page) map[string]interface{} {
+	result = paginate(result, "result", merge(words), p)
+
+	all := scores(words)
+	pageScores := make(map[string]float64)
+	for _, word := range result["result"].([]string) {
+		if score, ok := all[word]; ok {
+			pageScores[word] = score
+		}
+	}
+	result["scores"] = pageScores
+	return result
+}
+
+// paginateMorse merges the words found using each morse mapping and stores the requested page of them in the result
+// as paginateWords does. Words are counted across all of the mappings, and mappings with no words on the page are
+// omitted.
+func paginateMorse(result map[string]interface{}, groups []kowalski.MultiplexMorseGroup, p page) map[string]interface{} {
+	merged := make([][]string, len(groups))
+	all := make(map[string]float64)
+	for i := range groups {
+		merged[i] = merge(groups[i].Words)
+		maps.Copy(all, scores(groups[i].Words))
+	}
+
+	var paged []kowalski.MorseGroup
+	result = paginateGroups(result, merged, all, p, func(i int, words []string) {
+		paged = append(paged, kowalski.MorseGroup{Mapping: groups[i].Mapping, Words: words})
+	})
+	result["groups"] = paged
+	return result
+}
+
+// paginateLengths groups the words found by each checker by length as mergeByLength does, and stores the requested
+// page of them in the result as paginateMorse does.
+func paginateLengths(result map[string]interface{}, words [][]string, p page) map[string]interface{} {
+	groups := mergeByLength(words)
+	merged := make([][]string, len(groups))
+	for i := range groups {
+		merged[i] = groups[i].Words
+	}
+
+	var paged []kowalski.LengthGroup
+	result = paginateGroups(result, merged, scores(words), p, func(i int, words []string) {
+		paged = append(paged, kowalski.LengthGroup{Length: groups[i].Length, Words: words})
+	})
+	result["groups"] = paged
+	return result
+}
+
+// paginateGroups takes the requested page of words from across all of the groups, calling add with the index of each
+// group that has words on the page and those words. The offset, total, truncation and scores of the page are stored
+// in the result as paginateWords does.
+func paginateGroups(result map[string]interface{}, groups [][]string, all map[string]float64, p page, add func(int, []string)) map[string]interface{} {
+	total := 0
+	for i := range groups {
+		total += len(groups[i])
+	}
+	start, end := p.bounds(total)
+
+	pageScores := make(map[string]float64)
+	n := 0
+	for i := range groups {
+		from, to := max(start-n, 0), min(end-n, len(groups[i]))
+		n += len(groups[i])
+		if from >= to {
+			continue
+		}
+
+		for _, word := range groups[i][from:to] {
+			if score, ok := all[word]; ok {
+				pageScores[word] = score
+			}
+		}
+		add(i, groups[i][from:to])
+	}
+
+	result["offset"] = start
+	result["total"] = total
+	result["truncated"] = end < total
+	result["scores"] = pageScores
+	return result
+}
+
 func merge(words [][]string) []string {
 	var res []string
 	for i := range words {
 		for j := range words[i] {
-			if i > 0 {
-				res = append(res, fmt.Sprintf("_%s_", words[i][j]))
-			} else {
-				res = append(res, words[i][j])
+			res = append(res, mergedWord(i, words[i][j]))
+		}
+	}
+	return res
+}
+
+// mergedWord formats a word found by the checker with the given index, marking words from backup checkers.
+func mergedWord(checker int, word string) string {
+	if checker > 0 {
+		return fmt.Sprintf("_%s_", word)
+	}
+	return word
+}
+
+// scores returns the frequency score of each of the words found by the checkers, keyed by the word as formatted by
+// merge. Words without a score, including all those from checkers that don't rank their results, are omitted.
+func scores(words [][]string) map[string]float64 {
+	res := make(map[string]float64)
+	for i := range words {
+		for _, result := range kowalski.Rank(checkers[i], words[i]) {
+			if result.Score > 0 {
+				res[mergedWord(i, result.Result)] = result.Score
 			}
 		}
 	}
 	return res
 }
+
+// mergeByLength groups the words from each checker by length, longest first, formatting each group as merge does.
+func mergeByLength(words [][]string) []kowalski.LengthGroup {
+	byLength := make(map[int][][]string)
+	for i := range words {
+		for _, group := range kowalski.GroupByLength(words[i]) {
+			if byLength[group.Length] == nil {
+				byLength[group.Length] = make([][]string, len(words))
+			}
+			byLength[group.Length][i] = group.Words
+		}
+	}
+
+	lengths := slices.Sorted(maps.Keys(byLength))
+	slices.Reverse(lengths)
+
+	var res []kowalski.LengthGroup
+	for _, length := range lengths {
+		res = append(res, kowalski.LengthGroup{Length: length, Words: merge(byLength[length])})
+	}
+	return res
+}
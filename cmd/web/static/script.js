@@ -1,5 +1,9 @@
 let history = [];
 let currentCommandType = 'text';
+let disabledAnalysers = [];
+
+// Number of results requested per page for commands that support pagination
+const PAGE_SIZE = 100;
 
 // Load history from localStorage
 try {
@@ -12,11 +16,25 @@ try {
     localStorage.removeItem('kowalskiHistory');
 }
 
+// Load the analysers the user has turned off from localStorage
+try {
+    const storedAnalysers = localStorage.getItem('kowalskiDisabledAnalysers');
+    if (storedAnalysers) {
+        disabledAnalysers = JSON.parse(storedAnalysers);
+    }
+} catch (e) {
+    console.error('Failed to load analyser settings:', e);
+    localStorage.removeItem('kowalskiDisabledAnalysers');
+}
+
 document.addEventListener('DOMContentLoaded', () => {
     renderHistory();
     
     // Check if FST commands should be shown
     checkFSTAvailability();
+
+    loadModelInfo();
+    loadAnalysers();
     
     // Attach event listeners
     document.querySelectorAll('button[data-command]').forEach(button => {
@@ -48,6 +66,53 @@ async function checkFSTAvailability() {
     }
 }
 
+async function loadModelInfo() {
+    try {
+        const response = await fetch('/api/command', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ command: 'models', input: '' })
+        });
+        const data = await response.json();
+        if (data.success) {
+            document.getElementById('models').innerHTML = data.result.models.map(model =>
+                `<div><span class="result-item ${model.name === 'primary' ? '' : 'secondary'}">${escapeHtml(model.name)}</span> ${escapeHtml(model.description || 'no information available')}</div>`
+            ).join('');
+        }
+    } catch (error) {
+        console.log('Model information not available');
+    }
+}
+
+async function loadAnalysers() {
+    try {
+        const response = await fetch('/api/command', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ command: 'analysers', input: '' })
+        });
+        const data = await response.json();
+        if (data.success) {
+            // Forget analysers that are no longer available, as the server rejects unknown names
+            disabledAnalysers = disabledAnalysers.filter(name => data.result.analysers.some(analyser => analyser.name === name));
+            const container = document.getElementById('analysers');
+            container.innerHTML = '<span class="label">Analysers:</span>' + data.result.analysers.map(analyser =>
+                `<label title="${escapeHtml(analyser.tags.join(', '))}"><input type="checkbox" value="${escapeHtml(analyser.name)}" ${disabledAnalysers.includes(analyser.name) ? '' : 'checked'}> ${escapeHtml(analyser.name)}</label>`
+            ).join('');
+            container.querySelectorAll('input').forEach(checkbox => {
+                checkbox.addEventListener('change', saveAnalysers);
+            });
+        }
+    } catch (error) {
+        console.log('Analyser list not available');
+    }
+}
+
+function saveAnalysers() {
+    disabledAnalysers = Array.from(document.querySelectorAll('#analysers input:not(:checked)')).map(checkbox => checkbox.value);
+    localStorage.setItem('kowalskiDisabledAnalysers', JSON.stringify(disabledAnalysers));
+}
+
 async function executeCommand(command, type, special) {
     const input = document.getElementById('input').value.trim();
     
@@ -76,11 +141,16 @@ async function executeCommand(command, type, special) {
             finalInput = chunkSizes + ' ' + input;
         }
         
+        // Skip any analysers that have been turned off
+        if (special === 'analysis' && disabledAnalysers.length > 0) {
+            finalInput = 'skip=' + disabledAnalysers.join(',') + ' ' + input;
+        }
+        
         await executeTextCommand(command, finalInput);
     }
 }
 
-async function executeTextCommand(command, input) {
+async function executeTextCommand(command, input, offset = 0) {
     const historyItem = {
         command,
         input,
@@ -94,7 +164,7 @@ async function executeTextCommand(command, input) {
         const response = await fetch('/api/command', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ command, input })
+            body: JSON.stringify({ command, input, offset, limit: PAGE_SIZE })
         });
         
         const data = await response.json();
@@ -256,6 +326,7 @@ function renderHistory() {
             html += `<div class="error">Error: ${escapeHtml(item.error)}</div>`;
         } else if (item.result) {
             html += renderResult(item.command, item.result);
+            html += renderPagination(item.result, index);
         }
         
         html += '</div>';
@@ -265,23 +336,61 @@ function renderHistory() {
     });
 }
 
+function renderPagination(result, index) {
+    if (result.total === undefined || (!result.truncated && !result.offset)) {
+        return '';
+    }
+    
+    const shown = result.groups
+        ? result.groups.reduce((count, group) => count + group.words.length, 0)
+        : (result.result || result.matches || []).length;
+    const total = result.truncated ? `${result.total}+` : `${result.total}`;
+    let html = '<div class="pagination">';
+    if (result.offset > 0) {
+        html += `<button onclick="changePage(${index}, -1)">Previous</button>`;
+    }
+    html += `<span>Showing ${result.offset + 1}&ndash;${result.offset + shown} of ${total}</span>`;
+    if (result.truncated) {
+        html += `<button onclick="changePage(${index}, 1)">Next</button>`;
+    }
+    html += '</div>';
+    return html;
+}
+
+async function changePage(index, direction) {
+    const item = history[index];
+    const offset = Math.max(0, (item.result.offset || 0) + direction * PAGE_SIZE);
+    await executeTextCommand(item.command, item.input, offset);
+}
+
 function renderResult(command, result) {
     switch (command) {
         case 'anagram':
+        case 'fuzzy':
         case 'match':
-        case 'morse':
+        case 'multimorse':
         case 'multianagram':
         case 'multimatch':
         case 'offbyone':
         case 't9':
-            return renderWordList(result.result);
+            return renderWordList(result.result, result.scores);
             
         case 'analysis':
-            return renderAnalysis(result.result);
+            return renderAnalysis(result.result) +
+                (result.timedOut ? '<div class="error">Some analysers didn\'t finish in time</div>' : '');
             
         case 'chunk':
             return renderChunks(result.result);
             
+        case 'keyboard':
+            return renderKeyboardDecodings(result.decodings);
+            
+        case 'ladder':
+            return renderLadder(result);
+            
+        case 'morse':
+            return renderMorseGroups(result.groups, result.scores);
+            
         case 'letters':
             return renderLetterDistribution(result.distribution);
             
@@ -297,9 +406,24 @@ function renderResult(command, result) {
         case 'reverse':
             return `<pre>${escapeHtml(result.result)}</pre>`;
             
+        case 'crack':
+            return renderCandidates(result.candidates);
+            
+        case 'decode':
+            return renderDecodeChains(result.chains);
+            
+        case 'decrypt':
+        case 'encrypt':
+        case 'tomorse':
+        case 'tot9':
+            return `<pre>${escapeHtml(result.result)}</pre>`;
+            
         case 'checkwords':
             return renderCheckWords(result.result);
             
+        case 'subanagram':
+            return renderLengthGroups(result.groups, result.scores);
+            
         case 'wordsearch':
             return renderWordSearch(result);
             
@@ -316,6 +440,7 @@ function renderResult(command, result) {
         case 'fstanagram':
         case 'fstregex':
         case 'fstmorse':
+        case 'fstfuzzy':
             return renderFSTMatches(result.matches);
             
         case 'wordlink':
@@ -326,7 +451,7 @@ function renderResult(command, result) {
     }
 }
 
-function renderWordList(words) {
+function renderWordList(words, scores = {}) {
     if (!words || words.length === 0) {
         return '<div>No results found</div>';
     }
@@ -335,7 +460,8 @@ function renderWordList(words) {
     words.forEach(word => {
         const isSecondary = word.startsWith('_') && word.endsWith('_');
         const displayWord = isSecondary ? word.slice(1, -1) : word;
-        html += `<span class="result-item ${isSecondary ? 'secondary' : ''}">${escapeHtml(displayWord)}</span>`;
+        const score = scores[word] ? ` <span class="score">${Math.round(scores[word])}</span>` : '';
+        html += `<span class="result-item ${isSecondary ? 'secondary' : ''}">${escapeHtml(displayWord)}${score}</span>`;
     });
     html += '</div>';
     return html;
@@ -346,20 +472,43 @@ function renderAnalysis(results) {
         return '<div>Nothing interesting found</div>';
     }
     
-    let html = '<ul>';
+    let html = '<ul class="analysis">';
     results.forEach(item => {
-        html += `<li>${escapeHtml(item)}</li>`;
+        html += `<li class="analysis-${escapeHtml(item.category)}">`;
+        html += `${escapeHtml(item.message)} <span class="confidence">${Math.round(item.confidence * 100)}%</span>`;
+        if (item.command) {
+            const followUp = escapeHtml(item.command).replace(/"/g, '&quot;');
+            html += ` <button class="follow-up" data-follow-up="${followUp}" onclick="followUp(this)">${escapeHtml(item.command.split(' ')[0])}</button>`;
+        }
+        html += '</li>';
     });
     html += '</ul>';
     return html;
 }
 
+// Runs the command suggested by an analysis finding. The command name is followed by its input.
+async function followUp(button) {
+    const suggestion = button.dataset.followUp;
+    const space = suggestion.indexOf(' ');
+    await executeTextCommand(suggestion.substring(0, space), suggestion.substring(space + 1));
+}
+
 function renderChunks(chunks) {
     return `<div class="result-list">${chunks.map(chunk => 
         `<span class="result-item">${escapeHtml(chunk)}</span>`
     ).join('')}</div>`;
 }
 
+function renderLadder(result) {
+    if (!result.ladder || result.ladder.length === 0) {
+        return `<div>No word ladder found from '${escapeHtml(result.from)}' to '${escapeHtml(result.to)}'</div>`;
+    }
+    
+    return `<div class="result-list">${result.ladder.map(word => 
+        `<span class="result-item">${escapeHtml(word)}</span>`
+    ).join(' &rarr; ')}</div>`;
+}
+
 function renderLetterDistribution(distribution) {
     let html = '<div>';
     let max = Math.max(...Object.values(distribution));
@@ -394,6 +543,92 @@ function renderShifts(shifts) {
     return html;
 }
 
+function renderKeyboardDecodings(decodings) {
+    if (!decodings || decodings.length === 0) {
+        return '<div>No results found</div>';
+    }
+    
+    let html = '<div>';
+    decodings.forEach(decoding => {
+        const highlight = decoding.score > 0.5 ? 'highlight' : '';
+        html += `
+            <div class="shift-item ${highlight}">
+                <strong>${escapeHtml(decoding.method)}:</strong> ${escapeHtml(decoding.text)} 
+                <span style="color: #7f8c8d;">(${decoding.score.toFixed(5)})</span>
+            </div>
+        `;
+    });
+    html += '</div>';
+    return html;
+}
+
+function renderCandidates(candidates) {
+    if (!candidates || candidates.length === 0) {
+        return '<div>No results found</div>';
+    }
+    
+    let html = '<div>';
+    candidates.forEach((candidate, index) => {
+        const highlight = index === 0 ? 'highlight' : '';
+        html += `
+            <div class="shift-item ${highlight}">
+                <strong>Key ${escapeHtml(candidate.key)}:</strong> ${escapeHtml(candidate.plaintext)} 
+                <span style="color: #7f8c8d;">(${candidate.score.toFixed(3)})</span>
+            </div>
+        `;
+    });
+    html += '</div>';
+    return html;
+}
+
+function renderDecodeChains(chains) {
+    if (!chains || chains.length === 0) {
+        return '<div>No results found</div>';
+    }
+    
+    let html = '<div>';
+    chains.forEach((chain, index) => {
+        const highlight = index === 0 ? 'highlight' : '';
+        const final = chain.steps[chain.steps.length - 1];
+        html += `
+            <div class="shift-item ${highlight}">
+                <strong>${chain.steps.map(step => escapeHtml(step.decoder)).join(' &rarr; ')}:</strong> ${escapeHtml(final.output)} 
+                <span style="color: #7f8c8d;">(${final.score.toFixed(3)})</span>
+            </div>
+        `;
+    });
+    html += '</div>';
+    return html;
+}
+
+function renderLengthGroups(groups, scores) {
+    if (!groups || groups.length === 0) {
+        return '<div>No results found</div>';
+    }
+    
+    let html = '<div>';
+    groups.forEach(group => {
+        html += `<h4>${group.length} letters:</h4>`;
+        html += renderWordList(group.words, scores);
+    });
+    html += '</div>';
+    return html;
+}
+
+function renderMorseGroups(groups, scores) {
+    if (!groups || groups.length === 0) {
+        return '<div>No results found</div>';
+    }
+    
+    let html = '<div>';
+    groups.forEach(group => {
+        html += `<h4>${escapeHtml(group.mapping.description)}:</h4>`;
+        html += renderWordList(group.words, scores);
+    });
+    html += '</div>';
+    return html;
+}
+
 function renderWordSearch(result) {
     let html = '<div>';
     html += '<h4>Normal:</h4>';
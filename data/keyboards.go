@@ -46,3 +46,28 @@ var QwertyLowerTwoRows = append(QwertyLowerRow, QwertyHomeRow...)
 
 // QwertyOuterRows contains the characters in the outer two rows of a QWERTY keyboard.
 var QwertyOuterRows = append(QwertyLowerRow, QwertyUpperRow...)
+
+// QwertyKeys contains the unshifted characters on each row of a QWERTY keyboard, from the number row down.
+var QwertyKeys = []string{
+	"1234567890-=",
+	"qwertyuiop[]",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// DvorakKeys contains the unshifted characters on each row of a Dvorak keyboard, from the number row down.
+var DvorakKeys = []string{
+	"1234567890[]",
+	"',.pyfgcrl/=",
+	"aoeuidhtns-",
+	";qjkxbmwvz",
+}
+
+// AzertyKeys contains the characters on each row of a French AZERTY keyboard, from the number row down. The number
+// row is given with shift held, as the digits are otherwise unreachable.
+var AzertyKeys = []string{
+	"1234567890°+",
+	"azertyuiop^$",
+	"qsdfghjklmù",
+	"wxcvbn,;:!",
+}
@@ -0,0 +1,36 @@
+package kowalski
+
+import "iter"
+
+// Dictionary provides a way to tell whether a word, or the start of a word, exists in a word list.
+//
+// SpellChecker provides a compact, probabilistic implementation that may return false positives; WordList provides
+// an exact implementation at the cost of a larger model.
+type Dictionary interface {
+	// Valid determines whether the given word is in the dictionary.
+	Valid(word string) bool
+
+	// Prefix determines whether the given string is a prefix of any word in the dictionary.
+	Prefix(prefix string) bool
+}
+
+// Enumerator is a Dictionary that can also list the words it contains.
+type Enumerator interface {
+	Dictionary
+
+	// Words iterates over all words that start with the given prefix, in lexicographical order. If limit is greater
+	// than zero, at most that many words will be returned.
+	Words(prefix string, limit int) iter.Seq[string]
+
+	// Len returns the total number of words in the dictionary.
+	Len() int
+}
+
+// Weighted is a Dictionary that also knows how common each of its words are.
+type Weighted interface {
+	Dictionary
+
+	// Frequency returns a value indicating how common the word is; higher values are more common. Words that are not
+	// in the dictionary have a frequency of zero.
+	Frequency(word string) uint64
+}
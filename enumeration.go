@@ -0,0 +1,119 @@
+package kowalski
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Enumeration describes the lengths of the words in a crossword answer, as given in parentheses after a clue. For
+// example "(3,4)" is a three-letter word followed by a four-letter word, and "(5-3)" is a five-letter word hyphenated
+// to a three-letter word. The zero value is an empty enumeration that places no constraints on results.
+type Enumeration struct {
+	lengths    []int
+	separators []byte
+}
+
+// ParseEnumeration parses an enumeration such as "(3,4)" or "(5-3)". The parentheses are optional, and words may be
+// separated by commas, hyphens or spaces.
+func ParseEnumeration(input string) (Enumeration, error) {
+	inner := strings.TrimSpace(input)
+	if strings.HasPrefix(inner, "(") && strings.HasSuffix(inner, ")") {
+		inner = inner[1 : len(inner)-1]
+	}
+
+	var (
+		e     Enumeration
+		start = 0
+	)
+
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) && inner[i] >= '0' && inner[i] <= '9' {
+			continue
+		}
+
+		n, err := strconv.Atoi(inner[start:i])
+		if err != nil || n < 1 {
+			return Enumeration{}, fmt.Errorf("invalid enumeration %q", input)
+		}
+		e.lengths = append(e.lengths, n)
+
+		if i < len(inner) {
+			switch inner[i] {
+			case ',', ' ':
+				e.separators = append(e.separators, ' ')
+			case '-':
+				e.separators = append(e.separators, '-')
+			default:
+				return Enumeration{}, fmt.Errorf("invalid character %q in enumeration %q", inner[i], input)
+			}
+
+			// Allow a space after a comma, as in "(3, 4)"
+			if inner[i] == ',' && i+1 < len(inner) && inner[i+1] == ' ' {
+				i++
+			}
+		}
+		start = i + 1
+	}
+
+	return e, nil
+}
+
+// Lengths returns the length of each word in the enumeration.
+func (e Enumeration) Lengths() []int {
+	return slices.Clone(e.lengths)
+}
+
+// Words returns the number of words in the enumeration.
+func (e Enumeration) Words() int {
+	return len(e.lengths)
+}
+
+// Len returns the total number of letters in the enumeration.
+func (e Enumeration) Len() int {
+	total := 0
+	for _, l := range e.lengths {
+		total += l
+	}
+	return total
+}
+
+// Pattern returns a pattern consisting of a '?' wildcard for each letter in the enumeration.
+func (e Enumeration) Pattern() string {
+	return strings.Repeat("?", e.Len())
+}
+
+// String returns the enumeration in its usual form, e.g. "(3,4)".
+func (e Enumeration) String() string {
+	if len(e.lengths) == 0 {
+		return ""
+	}
+
+	b := &strings.Builder{}
+	b.WriteByte('(')
+	for i, l := range e.lengths {
+		if i > 0 {
+			if e.separators[i-1] == '-' {
+				b.WriteByte('-')
+			} else {
+				b.WriteByte(',')
+			}
+		}
+		b.WriteString(strconv.Itoa(l))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// join joins the words of a result using the separators from the enumeration.
+func (e Enumeration) join(words []string) string {
+	b := &strings.Builder{}
+	for i, word := range words {
+		if i > 0 {
+			b.WriteByte(e.separators[i-1])
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
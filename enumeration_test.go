@@ -0,0 +1,50 @@
+package kowalski
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEnumeration(t *testing.T) {
+	tests := []struct {
+		input   string
+		lengths []int
+		want    string
+		wantErr bool
+	}{
+		{"(3,4)", []int{3, 4}, "(3,4)", false},
+		{"(5-3)", []int{5, 3}, "(5-3)", false},
+		{"(3, 4)", []int{3, 4}, "(3,4)", false},
+		{"2,3-4", []int{2, 3, 4}, "(2,3-4)", false},
+		{"(10)", []int{10}, "(10)", false},
+		{"()", nil, "", true},
+		{"(3,,4)", nil, "", true},
+		{"(3,0)", nil, "", true},
+		{"(3/4)", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseEnumeration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEnumeration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got.Lengths(), tt.lengths) {
+				t.Errorf("Lengths() = %v, want %v", got.Lengths(), tt.lengths)
+			}
+			if got.String() != tt.want {
+				t.Errorf("String() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumerationJoin(t *testing.T) {
+	e, _ := ParseEnumeration("(3,4-2)")
+	if got, want := e.join([]string{"one", "four", "to"}), "one four-to"; got != want {
+		t.Errorf("join() = %q, want %q", got, want)
+	}
+	if got, want := e.Pattern(), "?????????"; got != want {
+		t.Errorf("Pattern() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package kowalski
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnagramExpression describes the letters available to an anagram, and words that the anagram must contain.
+type AnagramExpression struct {
+	// Letters are the letters to be anagrammed, including any '?' wildcards, in alphabetical order with wildcards last.
+	Letters string
+	// Contains lists words that each result must contain. Their letters are taken from Letters.
+	Contains []string
+}
+
+// ParseAnagramExpression parses an anagram expression. Expressions start with a set of letters (which may include '?'
+// wildcards), and may then add or remove letters using '+' and '-', e.g. "listen + ? - t". Removing a letter that
+// isn't present is an error. Fields of the form "with=word" require each result to contain the given word: for
+// single-word anagrams the word must appear within the result, and for multi-word anagrams it must be one of the
+// words. Whitespace is ignored.
+func ParseAnagramExpression(input string) (AnagramExpression, error) {
+	var (
+		expression AnagramExpression
+		terms      []string
+	)
+
+	for _, field := range strings.Fields(strings.ToLower(input)) {
+		if word, ok := strings.CutPrefix(field, "with="); ok {
+			if _, valid := newLetterPool(word); !valid || word == "" || strings.Contains(word, "?") {
+				return AnagramExpression{}, fmt.Errorf("invalid word %q in anagram expression", word)
+			}
+			expression.Contains = append(expression.Contains, word)
+			continue
+		}
+		terms = append(terms, field)
+	}
+
+	var (
+		pool     letterPool
+		operator byte = '+'
+		term     []byte
+		body     = strings.Join(terms, "")
+	)
+
+	apply := func() error {
+		if len(term) == 0 {
+			return fmt.Errorf("missing letters after %q in anagram expression %q", operator, input)
+		}
+
+		if operator == '+' {
+			added, _ := newLetterPool(string(term))
+			for i := range added.letters {
+				pool.letters[i] += added.letters[i]
+			}
+			pool.wildcards += added.wildcards
+			pool.size += added.size
+		} else {
+			for _, c := range term {
+				if c == '?' && pool.wildcards > 0 {
+					pool.wildcards--
+				} else if c != '?' && pool.letters[c-'a'] > 0 {
+					pool.letters[c-'a']--
+				} else {
+					return fmt.Errorf("cannot remove %q from anagram expression %q", c, input)
+				}
+				pool.size--
+			}
+		}
+
+		term = term[:0]
+		return nil
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c >= 'a' && c <= 'z', c == '?':
+			term = append(term, c)
+		case c == '+', c == '-':
+			if err := apply(); err != nil {
+				return AnagramExpression{}, err
+			}
+			operator = c
+		default:
+			return AnagramExpression{}, fmt.Errorf("invalid character %q in anagram expression %q", c, input)
+		}
+	}
+
+	if err := apply(); err != nil {
+		return AnagramExpression{}, err
+	}
+
+	expression.Letters = pool.String()
+	return expression, nil
+}
+
+// String returns the expression in a canonical form, with all additions and subtractions applied.
+func (e AnagramExpression) String() string {
+	var b strings.Builder
+	b.WriteString(e.Letters)
+	for _, word := range e.Contains {
+		b.WriteString(" with=")
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// pool returns the letters in the expression as a letterPool.
+func (e AnagramExpression) pool() letterPool {
+	pool, _ := newLetterPool(e.Letters)
+	return pool
+}
+
+// containedIn determines whether the given word contains every one of the expression's required words.
+func (e AnagramExpression) containedIn(word string) bool {
+	for _, contains := range e.Contains {
+		if !strings.Contains(word, contains) {
+			return false
+		}
+	}
+	return true
+}
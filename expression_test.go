@@ -0,0 +1,76 @@
+package kowalski
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAnagramExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    AnagramExpression
+		wantErr bool
+	}{
+		{"plain letters", "tac", AnagramExpression{Letters: "act"}, false},
+		{"wildcards", "t?c", AnagramExpression{Letters: "ct?"}, false},
+		{"addition", "cat + s", AnagramExpression{Letters: "acst"}, false},
+		{"subtraction", "cats - s", AnagramExpression{Letters: "act"}, false},
+		{"add and remove", "listen + ? - t", AnagramExpression{Letters: "eilns?"}, false},
+		{"no spaces", "listen+?-t", AnagramExpression{Letters: "eilns?"}, false},
+		{"remove wildcard", "ca?? - ?", AnagramExpression{Letters: "ac?"}, false},
+		{"upper case", "CAT + S", AnagramExpression{Letters: "acst"}, false},
+		{"contains", "cats + at with=cat", AnagramExpression{Letters: "aacstt", Contains: []string{"cat"}}, false},
+		{"multiple contains", "with=at cats with=s", AnagramExpression{Letters: "acst", Contains: []string{"at", "s"}}, false},
+		{"remove missing letter", "cat - s", AnagramExpression{}, true},
+		{"remove letter using wildcard", "ca? - s", AnagramExpression{}, true},
+		{"missing term", "cat + - s", AnagramExpression{}, true},
+		{"trailing operator", "cat +", AnagramExpression{}, true},
+		{"empty", "", AnagramExpression{}, true},
+		{"invalid character", "cat * 2", AnagramExpression{}, true},
+		{"invalid contains", "cats with=c?t", AnagramExpression{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAnagramExpression(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAnagramExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAnagramExpression() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnagramExpressions(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("a\nact\ncat\ncats\nat\nsat\nscat\ntas\ncatsat\n"))
+	ctx := context.Background()
+
+	if got, _ := Anagram(ctx, list, "cast - s + s"); !reflect.DeepEqual(got, []string{"cats", "scat"}) {
+		t.Errorf("Anagram() with additions and removals = %v", got)
+	}
+
+	if got, _ := Anagram(ctx, list, "tasc with=ats"); !reflect.DeepEqual(got, []string{"cats"}) {
+		t.Errorf("Anagram() with contained word = %v", got)
+	}
+
+	if got, _ := MultiAnagram(ctx, list, "cats + ta - ? with=sat", MultiWordOptions{}); got != nil {
+		t.Errorf("MultiAnagram() removing a wildcard that doesn't exist = %v, want nil", got)
+	}
+
+	if got, _ := MultiAnagram(ctx, list, "cats + ta with=sat", MultiWordOptions{}); !reflect.DeepEqual(got, []string{"act sat", "cat sat"}) {
+		t.Errorf("MultiAnagram() with contained word = %v", got)
+	}
+
+	if got, _ := SubAnagram(ctx, list, "cats + ? - ? with=at", SubAnagramOptions{}); !reflect.DeepEqual(got, []string{"at", "cat", "cats", "sat", "scat"}) {
+		t.Errorf("SubAnagram() with contained word = %v", got)
+	}
+
+	if _, err := Anagram(ctx, list, "cat - s"); err == nil {
+		t.Errorf("Anagram() with invalid expression returned no error")
+	}
+}
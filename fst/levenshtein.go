@@ -0,0 +1,52 @@
+package fst
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/blevesearch/vellum"
+	"github.com/blevesearch/vellum/levenshtein"
+)
+
+// MaxLevenshteinDistance is the largest edit distance supported by NewLevenshteinAutomaton. Building the automaton
+// for larger distances takes several seconds or more.
+const MaxLevenshteinDistance = 3
+
+var (
+	levenshteinBuilders    [MaxLevenshteinDistance + 1]*levenshtein.LevenshteinAutomatonBuilder
+	levenshteinBuilderLock sync.Mutex
+)
+
+// NewLevenshteinAutomaton creates a vellum Automaton that will match terms within the given edit distance of the
+// input, where inserting, deleting or substituting a letter, or transposing two adjacent letters, each count as one
+// edit. At most MaxLevenshteinDistance edits are supported.
+func NewLevenshteinAutomaton(term string, maxDistance int) (vellum.Automaton, error) {
+	if maxDistance < 0 || maxDistance > MaxLevenshteinDistance {
+		return nil, fmt.Errorf("invalid edit distance: %d (must be between 0 and %d)", maxDistance, MaxLevenshteinDistance)
+	}
+
+	builder, err := levenshteinBuilder(maxDistance)
+	if err != nil {
+		return nil, err
+	}
+
+	return builder.BuildDfa(strings.ToLower(term), uint8(maxDistance))
+}
+
+// levenshteinBuilder returns a builder for automata with the given maximum distance, creating it the first time it's
+// needed as doing so is relatively expensive.
+func levenshteinBuilder(maxDistance int) (*levenshtein.LevenshteinAutomatonBuilder, error) {
+	levenshteinBuilderLock.Lock()
+	defer levenshteinBuilderLock.Unlock()
+
+	if levenshteinBuilders[maxDistance] == nil {
+		builder, err := levenshtein.NewLevenshteinAutomatonBuilder(uint8(maxDistance), true)
+		if err != nil {
+			return nil, err
+		}
+		levenshteinBuilders[maxDistance] = builder
+	}
+
+	return levenshteinBuilders[maxDistance], nil
+}
@@ -2,61 +2,134 @@ package fst
 
 import (
 	"strings"
+	"unicode"
 
 	"github.com/blevesearch/vellum"
 )
 
 var morseLetters = map[byte]string{
-	'a': ".-",
-	'b': "-...",
-	'c': "-.-.",
-	'd': "-..",
-	'e': ".",
-	'f': "..-.",
-	'g': "--.",
-	'h': "....",
-	'i': "..",
-	'j': ".---",
-	'k': "-.-",
-	'l': ".-..",
-	'm': "--",
-	'n': "-.",
-	'o': "---",
-	'p': ".--.",
-	'q': "--.-",
-	'r': ".-.",
-	's': "...",
-	't': "-",
-	'u': "..-",
-	'v': "...-",
-	'w': ".--",
-	'x': "-..-",
-	'y': "-.--",
-	'z': "--..",
+	'a':  ".-",
+	'b':  "-...",
+	'c':  "-.-.",
+	'd':  "-..",
+	'e':  ".",
+	'f':  "..-.",
+	'g':  "--.",
+	'h':  "....",
+	'i':  "..",
+	'j':  ".---",
+	'k':  "-.-",
+	'l':  ".-..",
+	'm':  "--",
+	'n':  "-.",
+	'o':  "---",
+	'p':  ".--.",
+	'q':  "--.-",
+	'r':  ".-.",
+	's':  "...",
+	't':  "-",
+	'u':  "..-",
+	'v':  "...-",
+	'w':  ".--",
+	'x':  "-..-",
+	'y':  "-.--",
+	'z':  "--..",
+	'0':  "-----",
+	'1':  ".----",
+	'2':  "..---",
+	'3':  "...--",
+	'4':  "....-",
+	'5':  ".....",
+	'6':  "-....",
+	'7':  "--...",
+	'8':  "---..",
+	'9':  "----.",
+	'.':  ".-.-.-",
+	',':  "--..--",
+	'?':  "..--..",
+	'\'': ".----.",
+	'!':  "-.-.--",
+	'/':  "-..-.",
+	'(':  "-.--.",
+	')':  "-.--.-",
+	'&':  ".-...",
+	':':  "---...",
+	';':  "-.-.-.",
+	'=':  "-...-",
+	'+':  ".-.-.",
+	'-':  "-....-",
+	'_':  "..--.-",
+	'"':  ".-..-.",
+	'$':  "...-..-",
+	'@':  ".--.-.",
 }
 
 const errorSentinel = -1
 
 type morseAutomaton struct {
 	chars string
+	// spaced indicates every letter in the input is separated, so each term character must match a whole run.
+	spaced bool
+	// words indicates the input contains word separators, so spaces in terms must line up with them.
+	words bool
 }
 
-// NewMorseAutomaton creates a vellum Automaton that matches morse code input.
-// Any character other than '.' and '-' is ignored.
+// NewMorseAutomaton creates a vellum Automaton that matches morse code input. Whitespace in the input marks the
+// boundary between letters, and '/' or '|' the boundary between words; spaces in terms must then line up with the
+// word boundaries. If every run of signals in the input is a single letter, terms must match each run with exactly
+// one character. Any other character is ignored.
 func NewMorseAutomaton(term string) vellum.Automaton {
-	// Remove anything except - and .
-	pruned := strings.Builder{}
+	var (
+		pruned    = strings.Builder{}
+		separator byte
+		words     bool
+	)
+
 	for _, c := range term {
-		if c == '.' || c == '-' {
+		switch {
+		case c == '.' || c == '-':
+			if separator != 0 && pruned.Len() > 0 {
+				pruned.WriteByte(separator)
+				words = words || separator == '/'
+			}
+			separator = 0
 			pruned.WriteRune(c)
+		case c == '/' || c == '|':
+			separator = '/'
+		case unicode.IsSpace(c) && separator == 0:
+			separator = ' '
+		}
+	}
+
+	chars := pruned.String()
+	runs := strings.FieldsFunc(chars, func(r rune) bool {
+		return r == ' ' || r == '/'
+	})
+
+	spaced := len(runs) > 1
+	for _, run := range runs {
+		if !isMorseLetter(run) {
+			spaced = false
 		}
 	}
 
 	return &morseAutomaton{
-		chars: pruned.String(),
+		chars:  chars,
+		spaced: spaced,
+		words:  words,
 	}
 }
 
+// isMorseLetter determines whether the given signals are the code for a single letter.
+func isMorseLetter(signals string) bool {
+	for c := byte('a'); c <= 'z'; c++ {
+		if morseLetters[c] == signals {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *morseAutomaton) Start() int {
 	return 0
 }
@@ -74,9 +147,24 @@ func (m *morseAutomaton) WillAlwaysMatch(i int) bool {
 }
 
 func (m *morseAutomaton) Accept(i int, b byte) int {
+	if i == errorSentinel {
+		return errorSentinel
+	}
+
 	if b == ' ' {
-		// Skip over spaces
-		return i
+		if !m.words {
+			// Without word separators in the input, spaces can go anywhere
+			return i
+		}
+
+		if i < len(m.chars) && m.chars[i] == '/' {
+			return i + 1
+		}
+		return errorSentinel
+	}
+
+	if i < len(m.chars) && m.chars[i] == ' ' {
+		i++
 	}
 
 	if b >= 'A' && b <= 'Z' {
@@ -84,14 +172,18 @@ func (m *morseAutomaton) Accept(i int, b byte) int {
 	}
 
 	morse, ok := morseLetters[b]
-	if !ok {
+	if !ok || i > len(m.chars) {
 		return errorSentinel
 	}
 
 	remainder := m.chars[i:]
-	if strings.HasPrefix(remainder, morse) {
-		return i + len(morse)
+	if !strings.HasPrefix(remainder, morse) {
+		return errorSentinel
 	}
 
-	return errorSentinel
+	next := i + len(morse)
+	if m.spaced && next < len(m.chars) && m.chars[next] != ' ' && m.chars[next] != '/' {
+		return errorSentinel
+	}
+	return next
 }
@@ -0,0 +1,134 @@
+package kowalski
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/csmith/kowalski/v6/fst"
+)
+
+// Fuzzy finds all words within the given edit distance of the input, where inserting, deleting or substituting a
+// letter, or transposing two adjacent letters, each count as one edit. The input itself is not included. Unlike
+// OffByOne, the input may contain characters other than letters; they will simply need to be edited away.
+func Fuzzy(ctx context.Context, checker Dictionary, word string, maxDistance int) ([]string, error) {
+	if maxDistance < 0 {
+		return nil, fmt.Errorf("invalid edit distance: %d", maxDistance)
+	}
+	return collect(ctx, FuzzySeq(ctx, checker, word, maxDistance))
+}
+
+// FuzzySeq returns an iterator over the results of Fuzzy. Words are yielded as they are found; iteration stops early
+// if the context is cancelled, in which case callers should check ctx.Err().
+//
+// If the checker is a WordList, a Levenshtein automaton is run over its FST for distances of up to
+// fst.MaxLevenshteinDistance. Otherwise, candidates are built up a letter at a time, abandoning any prefix that
+// isn't in the dictionary or can't be completed within the edit distance.
+func FuzzySeq(ctx context.Context, checker Dictionary, word string, maxDistance int) iter.Seq[string] {
+	word = strings.ToLower(word)
+	return func(yield func(string) bool) {
+		if maxDistance < 0 {
+			return
+		}
+
+		if list, ok := checker.(*WordList); ok && maxDistance <= fst.MaxLevenshteinDistance {
+			if automaton, err := fst.NewLevenshteinAutomaton(word, maxDistance); err == nil {
+				for match := range list.search(automaton) {
+					if ctx.Err() != nil || (match != word && !yield(match)) {
+						return
+					}
+				}
+				return
+			}
+		}
+
+		target := []rune(word)
+		row := make([]int, len(target)+1)
+		for i := range row {
+			row[i] = i
+		}
+
+		findFuzzy(ctx, checker, target, maxDistance, nil, nil, row, func(match string) bool {
+			return match == word || yield(match)
+		})
+	}
+}
+
+// ParseFuzzyOptions splits an optional "distance=n" option from the rest of the input, returning the remaining
+// fields joined together and the maximum edit distance. If no distance is given, it defaults to 1.
+func ParseFuzzyOptions(input string) (string, int, error) {
+	var (
+		distance = 1
+		rest     []string
+	)
+
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
+
+		if !strings.EqualFold(key, "distance") {
+			return "", 0, fmt.Errorf("unknown option: %s", key)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return "", 0, fmt.Errorf("option %s requires a non-negative number, got %q", key, value)
+		}
+		distance = n
+	}
+
+	return strings.Join(rest, ""), distance, nil
+}
+
+// findFuzzy performs a depth-first search for words within maxDistance edits of the target. It maintains the rows of
+// an optimal string alignment distance table: row holds the distance between current and each prefix of the target,
+// and previous the same for current without its last letter (needed to account for transpositions). Returns false
+// if the search should stop.
+func findFuzzy(ctx context.Context, checker Dictionary, target []rune, maxDistance int, current []byte, previous, row []int, yield func(string) bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	for c := byte('a'); c <= 'z'; c++ {
+		next := make([]int, len(row))
+		next[0] = row[0] + 1
+		best := next[0]
+
+		for j := 1; j < len(next); j++ {
+			cost := 1
+			if target[j-1] == rune(c) {
+				cost = 0
+			}
+
+			next[j] = min(row[j]+1, next[j-1]+1, row[j-1]+cost)
+			if j > 1 && len(current) > 0 && target[j-1] == rune(current[len(current)-1]) && target[j-2] == rune(c) {
+				next[j] = min(next[j], previous[j-2]+1)
+			}
+			best = min(best, next[j])
+		}
+
+		if best > maxDistance {
+			continue
+		}
+
+		candidate := append(current, c)
+		if !checker.Prefix(string(candidate)) {
+			continue
+		}
+
+		if next[len(next)-1] <= maxDistance && checker.Valid(string(candidate)) && !yield(string(candidate)) {
+			return false
+		}
+
+		if !findFuzzy(ctx, checker, target, maxDistance, candidate, row, next, yield) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package kowalski
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// plainDictionary hides the Enumerator implementation of a WordList, so solvers fall back to prefix searches.
+type plainDictionary struct {
+	Dictionary
+}
+
+func TestFuzzy(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("cat\ncats\ncast\ncoat\nact\nat\ndog\nreceive\nrecipe\n"))
+
+	tests := []struct {
+		name     string
+		word     string
+		distance int
+		want     []string
+	}{
+		{"exact word excluded", "dog", 0, nil},
+		{"substitution", "dot", 1, []string{"dog"}},
+		{"single edits", "cat", 1, []string{"act", "at", "cast", "cats", "coat"}},
+		{"transposition", "recieve", 1, []string{"receive"}},
+		{"transposition counts once", "act", 1, []string{"at", "cat"}},
+		{"two edits", "cta", 2, []string{"act", "at", "cat", "cats", "coat"}},
+		{"non-letters", "c@t", 1, []string{"cat"}},
+		{"upper case", "DOT", 1, []string{"dog"}},
+		{"no matches", "zzzz", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Fuzzy(context.Background(), list, tt.word, tt.distance); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fuzzy() with automaton = %v, %v, want %v", got, err, tt.want)
+			}
+
+			if got, err := Fuzzy(context.Background(), plainDictionary{list}, tt.word, tt.distance); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fuzzy() with prefix search = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyInvalidDistance(t *testing.T) {
+	if _, err := Fuzzy(context.Background(), testChecker, "foo", -1); err == nil {
+		t.Errorf("Fuzzy() with negative distance returned no error")
+	}
+}
+
+func TestParseFuzzyOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantWord     string
+		wantDistance int
+		wantErr      bool
+	}{
+		{"default distance", "recieve", "recieve", 1, false},
+		{"distance", "recieve distance=2", "recieve", 2, false},
+		{"invalid distance", "recieve distance=x", "", 0, true},
+		{"unknown option", "recieve foo=2", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word, distance, err := ParseFuzzyOptions(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFuzzyOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if word != tt.wantWord || distance != tt.wantDistance {
+				t.Errorf("ParseFuzzyOptions() = %q, %d, want %q, %d", word, distance, tt.wantWord, tt.wantDistance)
+			}
+		})
+	}
+}
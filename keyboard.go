@@ -0,0 +1,247 @@
+package kowalski
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/csmith/kowalski/v6/data"
+)
+
+// KeyboardLayout describes the positions of the keys on a keyboard.
+type KeyboardLayout struct {
+	Name string
+	// Rows contains the unshifted characters on each row of keys, from the number row down.
+	Rows [][]rune
+}
+
+var (
+	// QwertyKeyboard is the standard US QWERTY layout.
+	QwertyKeyboard = newKeyboardLayout("QWERTY", data.QwertyKeys)
+	// DvorakKeyboard is the Dvorak simplified keyboard layout.
+	DvorakKeyboard = newKeyboardLayout("Dvorak", data.DvorakKeys)
+	// AzertyKeyboard is the French AZERTY layout.
+	AzertyKeyboard = newKeyboardLayout("AZERTY", data.AzertyKeys)
+)
+
+func newKeyboardLayout(name string, rows []string) KeyboardLayout {
+	layout := KeyboardLayout{Name: name}
+	for _, row := range rows {
+		layout.Rows = append(layout.Rows, []rune(row))
+	}
+	return layout
+}
+
+// position returns the row and column of the key that produces the given character.
+func (k KeyboardLayout) position(char rune) (int, int, bool) {
+	for row := range k.Rows {
+		if column := slices.Index(k.Rows[row], unicode.ToLower(char)); column != -1 {
+			return row, column, true
+		}
+	}
+	return 0, 0, false
+}
+
+// key returns the character at the given row and column, if there's a key there.
+func (k KeyboardLayout) key(row, column int) (rune, bool) {
+	if row < 0 || row >= len(k.Rows) || column < 0 || column >= len(k.Rows[row]) {
+		return 0, false
+	}
+	return k.Rows[row][column], true
+}
+
+// ShiftKeyboard replaces each character in the input with the key that is the given number of columns to the right
+// and rows down on the layout; negative numbers move left and up. Letters keep their case. Characters that aren't
+// on the layout, or that would move off the edge of the keyboard, are left unchanged.
+func ShiftKeyboard(input string, layout KeyboardLayout, columns, rows int) string {
+	return mapKeys(input, func(char rune) (rune, bool) {
+		row, column, ok := layout.position(char)
+		if !ok {
+			return 0, false
+		}
+		return layout.key(row+rows, column+columns)
+	})
+}
+
+// RemapKeyboard replaces each character in the input with the character at the same position on another layout. This
+// reverses the effect of typing on a keyboard with one layout while the computer expects another: text typed on a
+// QWERTY keyboard set up as Dvorak is restored with RemapKeyboard(input, DvorakKeyboard, QwertyKeyboard).
+func RemapKeyboard(input string, from, to KeyboardLayout) string {
+	return mapKeys(input, func(char rune) (rune, bool) {
+		row, column, ok := from.position(char)
+		if !ok {
+			return 0, false
+		}
+		return to.key(row, column)
+	})
+}
+
+// mapKeys replaces each character in the input using the mapping function, restoring the case of letters. Characters
+// the function doesn't map are left unchanged.
+func mapKeys(input string, mapping func(rune) (rune, bool)) string {
+	b := &strings.Builder{}
+	for _, char := range input {
+		replacement, ok := mapping(char)
+		switch {
+		case !ok:
+			b.WriteRune(char)
+		case unicode.IsUpper(char):
+			b.WriteRune(unicode.ToUpper(replacement))
+		default:
+			b.WriteRune(replacement)
+		}
+	}
+	return b.String()
+}
+
+// DecodeKeypadCoordinates decodes pairs of numbers that give a key on a phone keypad and the position of a character
+// on that key, such as "2,3" for "c" on the ITU layout. Pairs are separated by whitespace, and their numbers by one
+// of ",.-:" or nothing at all (so "23 33" is also accepted). A '/' separates words. If reversed, each pair gives the
+// position first and the key second. An error is returned if a pair doesn't refer to a character on the layout.
+func DecodeKeypadCoordinates(input string, layout T9Layout, reversed bool) (string, error) {
+	if layout == nil {
+		layout = ITULayout
+	}
+
+	b := &strings.Builder{}
+	for _, field := range strings.Fields(strings.ReplaceAll(input, "/", " / ")) {
+		if field == "/" {
+			b.WriteByte(' ')
+			continue
+		}
+
+		pairs, err := keypadPairs(field)
+		if err != nil {
+			return "", err
+		}
+
+		for _, pair := range pairs {
+			key, position := pair[0], pair[1]
+			if reversed {
+				key, position = position, key
+			}
+
+			var chars []rune
+			if key <= 9 {
+				chars = []rune(layout[rune('0'+key)])
+			}
+			if position < 1 || position > len(chars) {
+				return "", fmt.Errorf("no character at keypad position %d,%d", key, position)
+			}
+			b.WriteRune(chars[position-1])
+		}
+	}
+
+	if b.Len() == 0 {
+		return "", fmt.Errorf("no keypad coordinates found")
+	}
+	return b.String(), nil
+}
+
+// keypadPairs parses a field of keypad coordinates: either two numbers with a separator between them, or an even
+// number of digits which are taken in pairs.
+func keypadPairs(field string) ([][2]int, error) {
+	if i := strings.IndexAny(field, ",.-:"); i != -1 {
+		first, err1 := strconv.Atoi(field[:i])
+		second, err2 := strconv.Atoi(field[i+1:])
+		if err1 != nil || err2 != nil || first < 0 || second < 0 {
+			return nil, fmt.Errorf("invalid keypad coordinates: %s", field)
+		}
+		return [][2]int{{first, second}}, nil
+	}
+
+	if len(field)%2 != 0 || strings.Trim(field, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid keypad coordinates: %s", field)
+	}
+
+	var pairs [][2]int
+	for i := 0; i < len(field); i += 2 {
+		pairs = append(pairs, [2]int{int(field[i] - '0'), int(field[i+1] - '0')})
+	}
+	return pairs, nil
+}
+
+// KeyboardDecoding is a possible decoding of text that has been enciphered using a keyboard or keypad.
+type KeyboardDecoding struct {
+	// Method describes how the text was decoded, such as "QWERTY shifted one key left".
+	Method string `json:"method"`
+	// Text is the decoded text.
+	Text string `json:"text"`
+	// Score indicates how plausible the decoding is, from 0 (not at all) to 1 (entirely dictionary words).
+	Score float64 `json:"score"`
+}
+
+// DecodeKeyboard tries decoding the input as a keyboard or keypad cipher: shifting every key one position left,
+// right, up or down on the QWERTY, Dvorak and AZERTY layouts; remapping between QWERTY and the other layouts; and
+// reading pairs of numbers as ITU keypad coordinates in either order. Decodings are scored by how much of the text
+// consists of dictionary words, and returned with the most plausible first. Methods that fail or leave the input
+// unchanged are omitted.
+func DecodeKeyboard(checker Dictionary, input string) []KeyboardDecoding {
+	var results []KeyboardDecoding
+	add := func(method, text string) {
+		if text != input && !slices.ContainsFunc(results, func(d KeyboardDecoding) bool { return d.Text == text }) {
+			results = append(results, KeyboardDecoding{Method: method, Text: text, Score: scoreDecoding(checker, text)})
+		}
+	}
+
+	shifts := []struct {
+		name          string
+		columns, rows int
+	}{
+		{"left", -1, 0},
+		{"right", 1, 0},
+		{"up", 0, -1},
+		{"down", 0, 1},
+	}
+
+	for _, layout := range []KeyboardLayout{QwertyKeyboard, DvorakKeyboard, AzertyKeyboard} {
+		for _, shift := range shifts {
+			add(fmt.Sprintf("%s shifted one key %s", layout.Name, shift.name), ShiftKeyboard(input, layout, shift.columns, shift.rows))
+		}
+	}
+
+	for _, layout := range []KeyboardLayout{DvorakKeyboard, AzertyKeyboard} {
+		add(fmt.Sprintf("%s → %s", QwertyKeyboard.Name, layout.Name), RemapKeyboard(input, QwertyKeyboard, layout))
+		add(fmt.Sprintf("%s → %s", layout.Name, QwertyKeyboard.Name), RemapKeyboard(input, layout, QwertyKeyboard))
+	}
+
+	if text, err := DecodeKeypadCoordinates(input, ITULayout, false); err == nil {
+		add("keypad coordinates (key, position)", text)
+	}
+	if text, err := DecodeKeypadCoordinates(input, ITULayout, true); err == nil {
+		add("keypad coordinates (position, key)", text)
+	}
+
+	slices.SortStableFunc(results, func(a, b KeyboardDecoding) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return results
+}
+
+// scoreDecoding returns the proportion of letters in the text that are part of whitespace-separated dictionary
+// words, or the text's Score if that is higher (as it may be for text without spaces).
+func scoreDecoding(checker Dictionary, text string) float64 {
+	var valid, total int
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		cleaned := nonLetterRegex.ReplaceAllString(word, "")
+		total += len(cleaned)
+		if cleaned != "" && checker.Valid(cleaned) {
+			valid += len(cleaned)
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return math.Max(float64(valid)/float64(total), Score(checker, text))
+}
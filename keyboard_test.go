@@ -0,0 +1,111 @@
+package kowalski
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShiftKeyboard(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		layout        KeyboardLayout
+		columns, rows int
+		want          string
+	}{
+		{"right", "Hello there", QwertyKeyboard, 1, 0, "Jr;;p yjrtr"},
+		{"left", "Jr;;p yjrtr", QwertyKeyboard, -1, 0, "Hello there"},
+		{"up", "secret", QwertyKeyboard, 0, -1, "w3d435"},
+		{"off the edge", "qp", QwertyKeyboard, -1, 0, "qo"},
+		{"dvorak", "aoeu", DvorakKeyboard, 1, 0, "oeui"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShiftKeyboard(tt.input, tt.layout, tt.columns, tt.rows); got != tt.want {
+				t.Errorf("ShiftKeyboard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapKeyboard(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from, to KeyboardLayout
+		want     string
+	}{
+		{"qwerty to dvorak", "Hello world", QwertyKeyboard, DvorakKeyboard, "D.nnr ,rpne"},
+		{"dvorak to qwerty", "D.nnr ,rpne", DvorakKeyboard, QwertyKeyboard, "Hello world"},
+		{"azerty to qwerty", "qttqck qt dqzn", AzertyKeyboard, QwertyKeyboard, "attack at dawn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemapKeyboard(tt.input, tt.from, tt.to); got != tt.want {
+				t.Errorf("RemapKeyboard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeKeypadCoordinates(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		layout   T9Layout
+		reversed bool
+		want     string
+		wantErr  bool
+	}{
+		{"separated", "4,2 3,2 5,3 5,3 6,3", nil, false, "hello", false},
+		{"compact", "42 32 53 53 63 / 91 63 73 53 31", nil, false, "hello world", false},
+		{"unspaced", "4232535363", nil, false, "hello", false},
+		{"reversed", "2-4 2-3 3-5 3-5 3-6", nil, true, "hello", false},
+		{"nokia", "1:3", NokiaLayout, false, "'", false},
+		{"no such key", "1,1", nil, false, "", true},
+		{"no such position", "2,4", nil, false, "", true},
+		{"invalid", "2,x", nil, false, "", true},
+		{"odd digits", "234", nil, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeKeypadCoordinates(tt.input, tt.layout, tt.reversed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeKeypadCoordinates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeKeypadCoordinates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeKeyboard(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("hello\nthere\nworld\n"))
+
+	tests := []struct {
+		name       string
+		input      string
+		wantMethod string
+		wantText   string
+	}{
+		{"shift", "Jr;;p yjrtr", "QWERTY shifted one key left", "Hello there"},
+		{"remap", "D.nnr ,rpne", "Dvorak → QWERTY", "Hello world"},
+		{"keypad", "42 32 53 53 63", "keypad coordinates (key, position)", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeKeyboard(list, tt.input)
+			if len(got) == 0 {
+				t.Fatalf("DecodeKeyboard() returned no results")
+			}
+			if got[0].Method != tt.wantMethod || got[0].Text != tt.wantText || got[0].Score != 1 {
+				t.Errorf("DecodeKeyboard()[0] = %v, want %s: %s", got[0], tt.wantMethod, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package kowalski
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxLadderSteps is the maximum number of steps in a word ladder if none is given in the options.
+const defaultMaxLadderSteps = 10
+
+// LadderOptions configures the steps allowed by WordLadder.
+type LadderOptions struct {
+	// InsertDelete allows steps that insert or delete a single letter, in addition to those that change one.
+	InsertDelete bool
+	// MaxSteps is the maximum number of steps in a ladder. If zero, a ladder may have up to 10 steps.
+	MaxSteps int
+}
+
+// WordLadder finds the shortest chain of words leading from one word to another, where each word differs from the
+// previous one by a single letter, e.g. cat -> cot -> dot -> dog. The result includes both the starting and ending
+// words, which don't need to be in the dictionary themselves; every word in between does. If no ladder can be found
+// within the maximum number of steps, nil is returned.
+//
+// The search runs from both ends at once, always expanding whichever side has fewer words to explore, so that long
+// ladders don't require exploring every word within reach of the start.
+func WordLadder(ctx context.Context, checker Dictionary, from, to string, options LadderOptions) ([]string, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	for _, word := range []string{from, to} {
+		if !isLetters(word) {
+			return nil, fmt.Errorf("invalid word for ladder: %q", word)
+		}
+	}
+
+	if from == to {
+		return []string{from}, nil
+	}
+
+	if !options.InsertDelete && len(from) != len(to) {
+		return nil, nil
+	}
+
+	maxSteps := options.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = defaultMaxLadderSteps
+	}
+
+	var (
+		// forward maps each word reached from the start to the previous word in the ladder
+		forward = map[string]string{from: ""}
+		// backward maps each word reached from the end to the next word in the ladder
+		backward         = map[string]string{to: ""}
+		forwardFrontier  = []string{from}
+		backwardFrontier = []string{to}
+		meeting          string
+	)
+
+	for steps := 0; steps < maxSteps && len(forwardFrontier) > 0 && len(backwardFrontier) > 0; steps++ {
+		if len(forwardFrontier) <= len(backwardFrontier) {
+			forwardFrontier, meeting = expandLadder(ctx, checker, forwardFrontier, forward, backward, options)
+		} else {
+			backwardFrontier, meeting = expandLadder(ctx, checker, backwardFrontier, backward, forward, options)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if meeting != "" {
+			return joinLadder(meeting, forward, backward), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ParseLadderOptions splits options in the form "key=value" from the rest of the input, which must consist of the
+// two words to be linked. The supported keys are:
+//
+//   - steps: the maximum number of steps in the ladder
+//   - insertdelete: whether letters may be inserted or deleted ("true" or "false")
+//
+// For example, "cat dog steps=5" returns "cat" and "dog" with a MaxSteps of 5.
+func ParseLadderOptions(input string) (string, string, LadderOptions, error) {
+	var (
+		options LadderOptions
+		words   []string
+	)
+
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			words = append(words, field)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "steps":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return "", "", LadderOptions{}, fmt.Errorf("option %s requires a non-negative number, got %q", key, value)
+			}
+			options.MaxSteps = n
+		case "insertdelete":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return "", "", LadderOptions{}, fmt.Errorf("option %s requires true or false, got %q", key, value)
+			}
+			options.InsertDelete = b
+		default:
+			return "", "", LadderOptions{}, fmt.Errorf("unknown option: %s", key)
+		}
+	}
+
+	if len(words) != 2 {
+		return "", "", LadderOptions{}, fmt.Errorf("a word ladder requires two words, got %d", len(words))
+	}
+
+	return words[0], words[1], options, nil
+}
+
+// expandLadder explores one more step from each word in the frontier, recording the word each new word was reached
+// from in seen. It returns the new frontier, along with the first word found that has also been reached from the
+// other end of the ladder (or an empty string if there isn't one).
+func expandLadder(ctx context.Context, checker Dictionary, frontier []string, seen, other map[string]string, options LadderOptions) ([]string, string) {
+	var next []string
+	for _, word := range frontier {
+		if ctx.Err() != nil {
+			return nil, ""
+		}
+
+		for neighbour := range ladderSteps(word, options.InsertDelete) {
+			if _, ok := seen[neighbour]; ok {
+				continue
+			}
+
+			if _, ok := other[neighbour]; ok {
+				seen[neighbour] = word
+				return nil, neighbour
+			}
+
+			if checker.Valid(neighbour) {
+				seen[neighbour] = word
+				next = append(next, neighbour)
+			}
+		}
+	}
+	return next, ""
+}
+
+// ladderSteps returns an iterator over every string one step away from the given word. Candidates aren't checked
+// against a dictionary, and may be repeated when inserting or deleting letters.
+func ladderSteps(word string, insertDelete bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		buf := []byte(word)
+		for i := range buf {
+			original := buf[i]
+			for c := byte('a'); c <= 'z'; c++ {
+				if c == original {
+					continue
+				}
+				buf[i] = c
+				if !yield(string(buf)) {
+					return
+				}
+			}
+			buf[i] = original
+		}
+
+		if !insertDelete {
+			return
+		}
+
+		for i := 0; i <= len(word); i++ {
+			for c := byte('a'); c <= 'z'; c++ {
+				if !yield(word[:i] + string(c) + word[i:]) {
+					return
+				}
+			}
+		}
+
+		if len(word) > 1 {
+			for i := range word {
+				if !yield(word[:i] + word[i+1:]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// joinLadder builds the complete ladder passing through the given word, by following the links recorded in forward
+// back to the start and those in backward on to the end.
+func joinLadder(meeting string, forward, backward map[string]string) []string {
+	var ladder []string
+	for word := meeting; word != ""; word = forward[word] {
+		ladder = append(ladder, word)
+	}
+	slices.Reverse(ladder)
+
+	for word := backward[meeting]; word != ""; word = backward[word] {
+		ladder = append(ladder, word)
+	}
+	return ladder
+}
+
+// isLetters determines whether the input is non-empty and consists only of the letters a-z.
+func isLetters(input string) bool {
+	if input == "" {
+		return false
+	}
+
+	for i := range input {
+		if input[i] < 'a' || input[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
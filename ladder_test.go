@@ -0,0 +1,91 @@
+package kowalski
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordLadder(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("cat\ncot\ncog\ndog\ndot\ncoat\nboat\nbat\nat\nhat\nhate\n"))
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		options LadderOptions
+		want    []string
+	}{
+		{"same word", "cat", "cat", LadderOptions{}, []string{"cat"}},
+		{"single step", "cat", "cot", LadderOptions{}, []string{"cat", "cot"}},
+		{"multiple steps", "cat", "dog", LadderOptions{}, []string{"cat", "cot", "cog", "dog"}},
+		{"reversed", "dog", "cat", LadderOptions{}, []string{"dog", "cog", "cot", "cat"}},
+		{"endpoints not in dictionary", "cax", "dox", LadderOptions{}, []string{"cax", "cat", "cot", "dot", "dox"}},
+		{"step limit", "cat", "dog", LadderOptions{MaxSteps: 2}, nil},
+		{"different lengths", "cat", "coat", LadderOptions{}, nil},
+		{"insertion", "cat", "coat", LadderOptions{InsertDelete: true}, []string{"cat", "coat"}},
+		{"deletion", "hate", "at", LadderOptions{InsertDelete: true}, []string{"hate", "hat", "at"}},
+		{"mixed steps", "boat", "hat", LadderOptions{InsertDelete: true}, []string{"boat", "bat", "hat"}},
+		{"no ladder", "cat", "xyz", LadderOptions{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WordLadder(context.Background(), list, tt.from, tt.to, tt.options)
+			if err != nil {
+				t.Fatalf("WordLadder() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordLadder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordLadderInvalid(t *testing.T) {
+	if _, err := WordLadder(context.Background(), testChecker, "c4t", "dog", LadderOptions{}); err == nil {
+		t.Errorf("WordLadder() with invalid word returned no error")
+	}
+}
+
+func TestWordLadderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := WordLadder(ctx, testChecker, "foo", "bar", LadderOptions{}); err == nil {
+		t.Errorf("WordLadder() with cancelled context returned no error")
+	}
+}
+
+func TestParseLadderOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantFrom string
+		wantTo   string
+		want     LadderOptions
+		wantErr  bool
+	}{
+		{"words only", "cat dog", "cat", "dog", LadderOptions{}, false},
+		{"steps", "cat dog steps=5", "cat", "dog", LadderOptions{MaxSteps: 5}, false},
+		{"insert delete", "insertdelete=true cat dog", "cat", "dog", LadderOptions{InsertDelete: true}, false},
+		{"one word", "cat", "", "", LadderOptions{}, true},
+		{"three words", "cat dog cow", "", "", LadderOptions{}, true},
+		{"invalid steps", "cat dog steps=x", "", "", LadderOptions{}, true},
+		{"invalid bool", "cat dog insertdelete=maybe", "", "", LadderOptions{}, true},
+		{"unknown option", "cat dog foo=1", "", "", LadderOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, got, err := ParseLadderOptions(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLadderOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if from != tt.wantFrom || to != tt.wantTo || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLadderOptions() = %q, %q, %v, want %q, %q, %v", from, to, got, tt.wantFrom, tt.wantTo, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package kowalski
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// modelMagic is written at the start of every model file, so they can be distinguished from other files.
+var modelMagic = []byte("KWMD")
+
+// modelFormatVersion is the current version of the model file format.
+const modelFormatVersion = 1
+
+var (
+	// ErrUnknownModelFormat is returned when attempting to load something that isn't a Kowalski model.
+	ErrUnknownModelFormat = errors.New("unrecognised model format")
+
+	// ErrCorruptModel is returned when a model file is truncated or fails its checksum.
+	ErrCorruptModel = errors.New("model is corrupt")
+)
+
+// ModelKind identifies the type of dictionary stored in a model file.
+type ModelKind uint8
+
+const (
+	SpellCheckerModel ModelKind = 1
+	WordListModel     ModelKind = 2
+	NGramsModel       ModelKind = 3
+)
+
+func (k ModelKind) String() string {
+	switch k {
+	case SpellCheckerModel:
+		return "spell checker"
+	case WordListModel:
+		return "word list"
+	case NGramsModel:
+		return "n-gram model"
+	default:
+		return fmt.Sprintf("unknown (%d)", k)
+	}
+}
+
+// ModelInfo describes how a model was built. Models saved before metadata was introduced will have a Version of 0
+// and no other information.
+type ModelInfo struct {
+	Version                 int       `json:"version"`
+	Kind                    ModelKind `json:"kind"`
+	Source                  string    `json:"source,omitempty"`
+	WordCount               int       `json:"wordCount,omitempty"`
+	FalsePositiveRate       float64   `json:"falsePositiveRate,omitempty"`
+	PrefixFalsePositiveRate float64   `json:"prefixFalsePositiveRate,omitempty"`
+	NGramSize               int       `json:"ngramSize,omitempty"`
+	NGramCount              uint64    `json:"ngramCount,omitempty"`
+	Created                 time.Time `json:"created"`
+}
+
+// String returns a short, human-readable description of the model.
+func (i ModelInfo) String() string {
+	if i.Version == 0 {
+		return "legacy model (no metadata available)"
+	}
+
+	res := strings.Builder{}
+	if i.Source != "" {
+		res.WriteString(fmt.Sprintf("%s: ", i.Source))
+	}
+	if i.Kind == NGramsModel {
+		res.WriteString(fmt.Sprintf("%d-letter %s trained on %d n-grams", i.NGramSize, i.Kind, i.NGramCount))
+	} else {
+		res.WriteString(fmt.Sprintf("%s with %d words", i.Kind, i.WordCount))
+	}
+	if i.FalsePositiveRate > 0 {
+		res.WriteString(fmt.Sprintf(", %g false positive rate", i.FalsePositiveRate))
+	}
+	res.WriteString(fmt.Sprintf(", built %s", i.Created.Format(time.DateOnly)))
+	return res.String()
+}
+
+// Model is a Dictionary that was loaded from a model file, and can describe how it was built.
+type Model interface {
+	Dictionary
+	Info() ModelInfo
+}
+
+// LoadModel loads either a SpellChecker or a WordList, depending on the contents of the model file.
+func LoadModel(reader io.Reader) (Model, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, modelMagic) {
+		if checker, err := decodeLegacySpellChecker(data); err == nil {
+			return checker, nil
+		}
+		return nil, ErrUnknownModelFormat
+	}
+
+	info, body, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch info.Kind {
+	case SpellCheckerModel:
+		return decodeSpellChecker(body, info)
+	case WordListModel:
+		return newWordList(body, info)
+	case NGramsModel:
+		return nil, fmt.Errorf("%w: n-gram models are not dictionaries, use LoadNGrams instead", ErrUnknownModelFormat)
+	default:
+		return nil, fmt.Errorf("%w: unknown model kind %d", ErrUnknownModelFormat, info.Kind)
+	}
+}
+
+// encodeModel writes a model file containing the given info and body. The file consists of:
+//
+//   - the magic bytes "KWMD"
+//   - a single byte containing the format version
+//   - the JSON-encoded ModelInfo, prefixed with its length as a big-endian uint32
+//   - the body, prefixed with its length as a big-endian uint64
+//   - a big-endian CRC-32 (IEEE) checksum of everything preceding it
+func encodeModel(writer io.Writer, info ModelInfo, body []byte) error {
+	header, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.Write(modelMagic)
+	buffer.WriteByte(modelFormatVersion)
+	_ = binary.Write(buffer, binary.BigEndian, uint32(len(header)))
+	buffer.Write(header)
+	_ = binary.Write(buffer, binary.BigEndian, uint64(len(body)))
+	buffer.Write(body)
+	_ = binary.Write(buffer, binary.BigEndian, crc32.ChecksumIEEE(buffer.Bytes()))
+
+	_, err = writer.Write(buffer.Bytes())
+	return err
+}
+
+// decodeModel parses a model file previously written by encodeModel, returning the info and body.
+func decodeModel(data []byte) (ModelInfo, []byte, error) {
+	var info ModelInfo
+
+	if !bytes.HasPrefix(data, modelMagic) {
+		return info, nil, ErrUnknownModelFormat
+	}
+
+	if len(data) < len(modelMagic)+1+4+8+4 {
+		return info, nil, fmt.Errorf("%w: file is truncated", ErrCorruptModel)
+	}
+
+	checksum := binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(data[:len(data)-4]) != checksum {
+		return info, nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptModel)
+	}
+
+	offset := len(modelMagic)
+	if version := data[offset]; version > modelFormatVersion {
+		return info, nil, fmt.Errorf("%w: unsupported format version %d", ErrUnknownModelFormat, version)
+	}
+	offset++
+
+	headerLength := int(binary.BigEndian.Uint32(data[offset:]))
+	offset += 4
+	if headerLength > len(data)-offset-8-4 {
+		return info, nil, fmt.Errorf("%w: header length out of range", ErrCorruptModel)
+	}
+
+	if err := json.Unmarshal(data[offset:offset+headerLength], &info); err != nil {
+		return info, nil, fmt.Errorf("%w: unable to parse header: %v", ErrCorruptModel, err)
+	}
+	offset += headerLength
+
+	bodyLength := binary.BigEndian.Uint64(data[offset:])
+	offset += 8
+	if bodyLength != uint64(len(data)-offset-4) {
+		return info, nil, fmt.Errorf("%w: body length mismatch", ErrCorruptModel)
+	}
+
+	return info, data[offset : len(data)-4], nil
+}
+
+// ModelOption configures how a new SpellChecker or WordList is created.
+type ModelOption func(*modelOptions)
+
+type modelOptions struct {
+	source                  string
+	falsePositiveRate       float64
+	prefixFalsePositiveRate float64
+	prefixesPerWord         float64
+	countUnique             bool
+}
+
+// WithSource records the name of the word list a model was created from, so it can be shown to users later.
+func WithSource(source string) ModelOption {
+	return func(options *modelOptions) {
+		options.source = source
+	}
+}
+
+// WithFalsePositiveRate sets the target rate at which a SpellChecker will incorrectly identify a word as valid.
+// Lower rates result in larger models. The default is 0.001. This option has no effect on WordLists.
+func WithFalsePositiveRate(rate float64) ModelOption {
+	return func(options *modelOptions) {
+		options.falsePositiveRate = rate
+	}
+}
+
+// WithPrefixFalsePositiveRate sets the target rate at which a SpellChecker will incorrectly identify a string as a
+// prefix of a valid word. Lower rates result in larger models, but allow solvers to prune their searches more
+// aggressively. The default is 0.1. This option has no effect on WordLists.
+func WithPrefixFalsePositiveRate(rate float64) ModelOption {
+	return func(options *modelOptions) {
+		options.prefixFalsePositiveRate = rate
+	}
+}
+
+// WithPrefixesPerWord sets the estimated number of unique prefixes per word, which is used to size a SpellChecker's
+// prefix filter. The default is 10. This option is ignored if CountUniqueWords is used, as the number of prefixes is
+// then counted exactly. It has no effect on WordLists.
+func WithPrefixesPerWord(prefixes float64) ModelOption {
+	return func(options *modelOptions) {
+		options.prefixesPerWord = prefixes
+	}
+}
+
+// CountUniqueWords makes CreateSpellChecker read the entire word list up front, and size its filters using the exact
+// number of unique words and prefixes instead of the supplied estimate. Duplicate words are ignored.
+func CountUniqueWords(options *modelOptions) {
+	options.countUnique = true
+}
+
+func applyModelOptions(opts []ModelOption) (*modelOptions, error) {
+	o := &modelOptions{
+		falsePositiveRate:       0.001,
+		prefixFalsePositiveRate: 0.1,
+		prefixesPerWord:         10,
+	}
+	for i := range opts {
+		opts[i](o)
+	}
+
+	if o.falsePositiveRate <= 0 || o.falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("false positive rate must be between 0 and 1, got %g", o.falsePositiveRate)
+	}
+
+	if o.prefixFalsePositiveRate <= 0 || o.prefixFalsePositiveRate >= 1 {
+		return nil, fmt.Errorf("prefix false positive rate must be between 0 and 1, got %g", o.prefixFalsePositiveRate)
+	}
+
+	if o.prefixesPerWord <= 0 {
+		return nil, fmt.Errorf("prefixes per word must be positive, got %g", o.prefixesPerWord)
+	}
+
+	return o, nil
+}
+
+// readWords reads words line-by-line from the reader, returning them lowercased, sorted and without duplicates.
+// Empty lines are ignored. Any frequencies given (see ParseWord) are returned in the map, with the frequencies of
+// duplicate words summed.
+func readWords(reader io.Reader) ([]string, map[string]uint64, error) {
+	var words []string
+	frequencies := make(map[string]uint64)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if word, frequency := ParseWord(scanner.Text()); word != "" {
+			words = append(words, word)
+			frequencies[word] += frequency
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Strings(words)
+	return slices.Compact(words), frequencies, nil
+}
+
+// ParseWord parses a line from a word list, as read by CreateSpellChecker and CreateWordList. Lines may optionally
+// contain a frequency after a tab character, e.g. "word\t1234"; if no frequency is present it will be returned as zero.
+func ParseWord(line string) (string, uint64) {
+	word, count, found := strings.Cut(line, "\t")
+	if !found {
+		return strings.ToLower(line), 0
+	}
+
+	frequency, err := strconv.ParseUint(strings.TrimSpace(count), 10, 64)
+	if err != nil {
+		return strings.ToLower(line), 0
+	}
+	return strings.ToLower(word), frequency
+}
+
+// countPrefixes returns the number of unique non-empty prefixes of the given words, which must be sorted.
+func countPrefixes(words []string) int {
+	count := 0
+	previous := ""
+	for _, word := range words {
+		common := 0
+		for common < len(word) && common < len(previous) && word[common] == previous[common] {
+			common++
+		}
+		count += len(word) - common
+		previous = word
+	}
+	return count
+}
@@ -0,0 +1,100 @@
+package kowalski
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/bits-and-blooms/bloom/v3"
+)
+
+func TestLoadModel(t *testing.T) {
+	checkerBuffer := &bytes.Buffer{}
+	_ = SaveSpellChecker(checkerBuffer, testChecker)
+
+	listBuffer := &bytes.Buffer{}
+	_ = SaveWordList(listBuffer, testWordList)
+
+	legacyBuffer := &bytes.Buffer{}
+	_ = gob.NewEncoder(legacyBuffer).Encode([]*bloom.BloomFilter{
+		testChecker.primary,
+		testChecker.secondaries[0],
+		testChecker.secondaries[1],
+		testChecker.roots,
+	})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		kind    ModelKind
+		version int
+	}{
+		{"spell checker", checkerBuffer.Bytes(), SpellCheckerModel, modelFormatVersion},
+		{"word list", listBuffer.Bytes(), WordListModel, modelFormatVersion},
+		{"legacy spell checker", legacyBuffer.Bytes(), SpellCheckerModel, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := LoadModel(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("LoadModel() error = %v", err)
+			}
+
+			if info := model.Info(); info.Kind != tt.kind || info.Version != tt.version {
+				t.Errorf("LoadModel() info = %+v, want kind %v and version %d", info, tt.kind, tt.version)
+			}
+
+			if !model.Valid("quux") {
+				t.Errorf("LoadModel() returned a model that doesn't contain quux")
+			}
+		})
+	}
+}
+
+func TestLoadModelErrors(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	_ = SaveWordList(buffer, testWordList)
+	valid := buffer.Bytes()
+
+	corrupt := append([]byte{}, valid...)
+	corrupt[len(corrupt)/2] ^= 0xff
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"foreign file", []byte("this is not a model"), ErrUnknownModelFormat},
+		{"empty file", nil, ErrUnknownModelFormat},
+		{"corrupt file", corrupt, ErrCorruptModel},
+		{"truncated file", valid[:len(valid)-10], ErrCorruptModel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadModel(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("LoadModel() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadShippedModel(t *testing.T) {
+	f, err := os.Open("models/enable.wl")
+	if err != nil {
+		t.Skipf("Unable to open shipped model: %v", err)
+	}
+	defer f.Close()
+
+	checker, err := LoadSpellChecker(f)
+	if err != nil {
+		t.Fatalf("LoadSpellChecker() error = %v", err)
+	}
+
+	if !checker.Valid("analysis") {
+		t.Errorf("Shipped model doesn't contain expected words")
+	}
+}
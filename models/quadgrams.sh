@@ -0,0 +1,30 @@
+#!/bin/sh
+# Rebuilds quadgrams.ng from a fixed set of public-domain books on Project
+# Gutenberg. Run from the repository root: ./models/quadgrams.sh
+#
+# Each book's Project Gutenberg header and licence footer are stripped before
+# training so that the boilerplate doesn't skew the model. Downloads are cached
+# in $CORPUS_DIR (default: .corpus) so that re-runs don't hit Gutenberg again.
+
+set -eu
+
+CORPUS_DIR="${CORPUS_DIR:-.corpus}"
+
+# War and Peace, History of the United States (Beard and Beard), The Adventures of Sherlock Holmes
+BOOKS="2600 16960 1661"
+
+mkdir -p "$CORPUS_DIR"
+
+for book in $BOOKS; do
+  file="$CORPUS_DIR/pg$book.txt"
+  if [ ! -s "$file" ]; then
+    curl -fsSL -o "$file" "https://www.gutenberg.org/cache/epub/$book/pg$book.txt"
+  fi
+done
+
+for book in $BOOKS; do
+  sed -e '1,/^\*\*\* *START OF TH[EI]S* PROJECT GUTENBERG EBOOK/d' \
+      -e '/^\*\*\* *END OF TH[EI]S* PROJECT GUTENBERG EBOOK/,$d' \
+      "$CORPUS_DIR/pg$book.txt"
+done | go run ./cmd/ngrams -n 4 -out models/quadgrams.ng \
+  -source "Project Gutenberg books $(echo $BOOKS | tr ' ' ',')"
@@ -1,62 +1,551 @@
 package kowalski
 
 import (
+	"context"
 	"fmt"
-	"regexp"
+	"iter"
+	"slices"
 	"strings"
+	"unicode"
 )
 
-var morseLetters = map[string]rune{
-	".-":   'a',
-	"-...": 'b',
-	"-.-.": 'c',
-	"-..":  'd',
-	".":    'e',
-	"..-.": 'f',
-	"--.":  'g',
-	"....": 'h',
-	"..":   'i',
-	".---": 'j',
-	"-.-":  'k',
-	".-..": 'l',
-	"--":   'm',
-	"-.":   'n',
-	"---":  'o',
-	".--.": 'p',
-	"--.-": 'q',
-	".-.":  'r',
-	"...":  's',
-	"-":    't',
-	"..-":  'u',
-	"...-": 'v',
-	".--":  'w',
-	"-..-": 'x',
-	"-.--": 'y',
-	"--..": 'z',
-}
-
-var nonMorseRegexp = regexp.MustCompile("[^.\\-]")
-
-// FromMorse takes a sequence of morse signals (as ASCII dots and hyphens) and returns a set of possible words
-// that could be constructed from them.
-func FromMorse(checker *SpellChecker, input string) []string {
-	return fromMorse(checker, nonMorseRegexp.ReplaceAllString(input, ""), "")
-}
-
-func fromMorse(checker *SpellChecker, input string, prefix string) []string {
-	var res []string
-
-	for p := range morseLetters {
-		if strings.HasPrefix(input, p) {
-			next := fmt.Sprintf("%s%c", prefix, morseLetters[p])
-			left := input[len(p):]
-			if len(left) == 0 && checker.Valid(next) {
-				res = append(res, next)
-			} else if len(left) > 0 && checker.Prefix(next) {
-				res = append(res, fromMorse(checker, left, next)...)
-			}
-		}
-	}
-
-	return res
+// morseCodes maps each character in the ITU morse code table to its code.
+var morseCodes = map[rune]string{
+	'a':  ".-",
+	'b':  "-...",
+	'c':  "-.-.",
+	'd':  "-..",
+	'e':  ".",
+	'f':  "..-.",
+	'g':  "--.",
+	'h':  "....",
+	'i':  "..",
+	'j':  ".---",
+	'k':  "-.-",
+	'l':  ".-..",
+	'm':  "--",
+	'n':  "-.",
+	'o':  "---",
+	'p':  ".--.",
+	'q':  "--.-",
+	'r':  ".-.",
+	's':  "...",
+	't':  "-",
+	'u':  "..-",
+	'v':  "...-",
+	'w':  ".--",
+	'x':  "-..-",
+	'y':  "-.--",
+	'z':  "--..",
+	'0':  "-----",
+	'1':  ".----",
+	'2':  "..---",
+	'3':  "...--",
+	'4':  "....-",
+	'5':  ".....",
+	'6':  "-....",
+	'7':  "--...",
+	'8':  "---..",
+	'9':  "----.",
+	'.':  ".-.-.-",
+	',':  "--..--",
+	'?':  "..--..",
+	'\'': ".----.",
+	'!':  "-.-.--",
+	'/':  "-..-.",
+	'(':  "-.--.",
+	')':  "-.--.-",
+	'&':  ".-...",
+	':':  "---...",
+	';':  "-.-.-.",
+	'=':  "-...-",
+	'+':  ".-.-.",
+	'-':  "-....-",
+	'_':  "..--.-",
+	'"':  ".-..-.",
+	'$':  "...-..-",
+	'@':  ".--.-.",
+}
+
+// morseLetters maps each morse code back to its character.
+var morseLetters = make(map[string]rune, len(morseCodes))
+
+// morseSymbol is a single entry from the morse code table.
+type morseSymbol struct {
+	char rune
+	code string
+}
+
+// morseSymbols lists every entry in the morse code table, ordered by character, so that searches are deterministic.
+var morseSymbols []morseSymbol
+
+func init() {
+	for char, code := range morseCodes {
+		morseLetters[code] = char
+		morseSymbols = append(morseSymbols, morseSymbol{char: char, code: code})
+	}
+
+	slices.SortFunc(morseSymbols, func(a, b morseSymbol) int {
+		return int(a.char) - int(b.char)
+	})
+}
+
+// FromMorse takes a sequence of morse signals (as ASCII dots and hyphens) and returns a set of possible words that
+// could be constructed from them. Spaces mark known boundaries between letters, and '/' marks boundaries between
+// words; each word-separated part of the input is decoded into a single word, and the words are joined with spaces.
+// If every run of signals between separators is the code for a letter, the input is taken to be fully spaced and
+// each run is decoded as a single character. Otherwise, runs may contain any number of letters. Other characters are
+// ignored.
+func FromMorse(checker Dictionary, input string) []string {
+	return slices.Collect(FromMorseSeq(context.Background(), checker, input))
+}
+
+// FromMorseSeq returns an iterator over the words that FromMorse would return. Words are yielded as they are found;
+// iteration stops early if the context is cancelled, in which case callers should check ctx.Err().
+func FromMorseSeq(ctx context.Context, checker Dictionary, input string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		fromMorse(ctx, checker, parseMorse(input), false, MultiWordOptions{}, yield)
+	}
+}
+
+// MultiFromMorse decodes morse signals in the same way as FromMorse, but allows any part of the input that isn't
+// separated into words to be split into several. The results can be constrained using options; if no minimum word
+// length is given, words must be at least two letters long, and if no maximum number of words is given, results are
+// limited to three words (or one per word-separated part of the input, if there are more).
+func MultiFromMorse(ctx context.Context, checker Dictionary, input string, options MultiWordOptions) ([]string, error) {
+	return collect(ctx, MultiFromMorseSeq(ctx, checker, input, options))
+}
+
+// MultiFromMorseSeq returns an iterator over the results of MultiFromMorse. Results are yielded as they are found;
+// iteration stops early if the context is cancelled, in which case callers should check ctx.Err().
+func MultiFromMorseSeq(ctx context.Context, checker Dictionary, input string, options MultiWordOptions) iter.Seq[string] {
+	if options.MinWordLength == 0 {
+		options.MinWordLength = 2
+	}
+
+	return func(yield func(string) bool) {
+		parsed := parseMorse(input)
+		if options.MaxWords == 0 {
+			options.MaxWords = max(defaultMaxVariableWords, strings.Count(parsed.signals, "/")+1)
+		}
+		fromMorse(ctx, checker, parsed, true, options, yield)
+	}
+}
+
+// ParseMorseOptions splits multi-word options from morse input in the same way as ParseMultiWordOptions, but keeps
+// the remaining fields separated by spaces so that letter boundaries are preserved.
+func ParseMorseOptions(input string) (string, MultiWordOptions, error) {
+	rest, options, err := parseMultiWordOptions(input)
+	if err != nil {
+		return "", MultiWordOptions{}, err
+	}
+	return strings.Join(rest, " "), options, nil
+}
+
+// DecodeMorse decodes morse signals where every character is separated by a space, and words by '/', as produced by
+// ToMorse. Decoded words are separated by spaces. An error is returned if any code isn't in the ITU table.
+func DecodeMorse(input string) (string, error) {
+	parsed := parseMorse(input)
+
+	var words []string
+	for _, word := range strings.Split(parsed.signals, "/") {
+		b := &strings.Builder{}
+		for _, code := range strings.Fields(word) {
+			char, ok := morseLetters[code]
+			if !ok {
+				return "", fmt.Errorf("invalid morse code: %s", code)
+			}
+			b.WriteRune(char)
+		}
+		words = append(words, b.String())
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ToMorse encodes the input as morse code, separating characters with spaces and words with " / ". Letters are
+// case-insensitive. An error is returned if the input contains a character that isn't in the ITU table.
+func ToMorse(input string) (string, error) {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(input)) {
+		var codes []string
+		for _, char := range word {
+			code, ok := morseCodes[char]
+			if !ok {
+				return "", fmt.Errorf("character %q cannot be encoded as morse", char)
+			}
+			codes = append(codes, code)
+		}
+		words = append(words, strings.Join(codes, " "))
+	}
+	return strings.Join(words, " / "), nil
+}
+
+// morseInput is morse code normalised to dots and dashes, with a single space between letters known to be separate
+// and a '/' between words known to be separate.
+type morseInput struct {
+	signals string
+	// spaced indicates that every run of signals is a single letter, so the input is assumed to be fully spaced.
+	spaced bool
+}
+
+// parseMorse normalises morse code input. Common alternatives for dots and dashes (such as '·' and '_') are
+// accepted, any whitespace separates letters, and '/' or '|' separate words. Other characters are ignored.
+func parseMorse(input string) morseInput {
+	var (
+		b         = &strings.Builder{}
+		separator byte
+	)
+
+	for _, r := range input {
+		var c byte
+		switch r {
+		case '.', '·', '•', '∙':
+			c = '.'
+		case '-', '_', '−', '–', '—':
+			c = '-'
+		case '/', '|':
+			separator = '/'
+			continue
+		default:
+			if unicode.IsSpace(r) && separator == 0 {
+				separator = ' '
+			}
+			continue
+		}
+
+		if separator != 0 && b.Len() > 0 {
+			b.WriteByte(separator)
+		}
+		separator = 0
+		b.WriteByte(c)
+	}
+
+	signals := b.String()
+	runs := strings.FieldsFunc(signals, func(r rune) bool {
+		return r == ' ' || r == '/'
+	})
+
+	spaced := len(runs) > 1
+	for _, run := range runs {
+		if char, ok := morseLetters[run]; !ok || char < 'a' || char > 'z' {
+			spaced = false
+		}
+	}
+
+	return morseInput{signals: signals, spaced: spaced}
+}
+
+// fromMorse performs a depth-first search for ways to decode the morse input into dictionary words, passing each
+// complete result to yield. Word boundaries are always placed at '/' separators; if multiWord is set, they may also
+// be placed between any two letters. Returns false if the search should stop.
+func fromMorse(ctx context.Context, checker Dictionary, input morseInput, multiWord bool, options MultiWordOptions, yield func(string) bool) bool {
+	var (
+		signals = input.signals
+		words   []string
+		search  func(i int, current string) bool
+	)
+
+	if signals == "" {
+		return true
+	}
+
+	search = func(i int, current string) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if i == len(signals) {
+			if options.allowsWord(len(words), len(current)) && checker.Valid(current) {
+				result := append(words, current)
+				if options.accepts(result) {
+					return yield(options.join(result))
+				}
+			}
+			return true
+		}
+
+		switch signals[i] {
+		case ' ':
+			return search(i+1, current)
+		case '/':
+			if !options.allowsWord(len(words), len(current)) || !options.allowsMoreWords(len(words)+1) || !checker.Valid(current) {
+				return true
+			}
+
+			words = append(words, current)
+			ok := search(i+1, "")
+			words = words[:len(words)-1]
+			return ok
+		}
+
+		for _, symbol := range morseSymbols {
+			if !strings.HasPrefix(signals[i:], symbol.code) {
+				continue
+			}
+
+			end := i + len(symbol.code)
+			if input.spaced && end < len(signals) && signals[end] != ' ' && signals[end] != '/' {
+				continue
+			}
+
+			next := current + string(symbol.char)
+			if !options.allowsPrefix(len(words), len(next)) || !checker.Prefix(next) {
+				continue
+			}
+
+			if !search(end, next) {
+				return false
+			}
+
+			if multiWord && end < len(signals) && signals[end] != '/' && options.allowsWord(len(words), len(next)) && options.allowsMoreWords(len(words)+1) && checker.Valid(next) {
+				words = append(words, next)
+				ok := search(end, "")
+				words = words[:len(words)-1]
+				if !ok {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	return search(0, "")
+}
+
+// MorseMapping describes one way of reading an input as morse code.
+type MorseMapping struct {
+	// Description explains how symbols in the input were mapped to morse signals, such as "0 = dot, 1 = dash".
+	Description string `json:"description"`
+	// Signals is the input translated into standard morse code.
+	Signals string `json:"signals"`
+}
+
+// MorseGroup is a set of words decoded from an input using a particular mapping.
+type MorseGroup struct {
+	Mapping MorseMapping `json:"mapping"`
+	Words   []string     `json:"words"`
+}
+
+// MorseMappings returns the plausible ways of reading the input as morse code, translated into standard dots and
+// dashes. Whitespace separates letters and '/' or '|' separates words, as with FromMorse.
+//
+// Input made of dots and dashes is read as-is and with the two swapped. Input made of any other two symbols (such as 0
+// and 1) is read with each symbol as the dot in turn, and input made of three symbols additionally tries each as a
+// letter separator. Input made of any other number of symbols is also split into whitespace-separated tokens (such as
+// short and long words, or runs of block characters), reading shorter tokens as dots and longer ones as dashes, trying
+// each possible threshold. In that case, if the gaps between tokens vary in size, the smallest gaps fall within
+// letters, the next smallest separate letters, and any larger gaps separate words.
+func MorseMappings(input string) []MorseMapping {
+	var mappings []MorseMapping
+	seen := make(map[string]bool)
+	add := func(description, signals string) {
+		signals = parseMorse(signals).signals
+		if signals != "" && !seen[signals] {
+			seen[signals] = true
+			mappings = append(mappings, MorseMapping{Description: description, Signals: signals})
+		}
+	}
+
+	symbols := morseSymbolsIn(input)
+	standard := isStandardMorse(symbols)
+	switch {
+	case len(symbols) == 0:
+		return nil
+	case standard:
+		add("standard", input)
+		add("dots and dashes swapped", translateMorse(input, map[rune]rune{'.': '-', '-': '.'}))
+	case len(symbols) == 2:
+		slices.Sort(symbols)
+		for _, pair := range [][2]rune{{symbols[0], symbols[1]}, {symbols[1], symbols[0]}} {
+			add(
+				fmt.Sprintf("%c = dot, %c = dash", pair[0], pair[1]),
+				translateMorse(input, map[rune]rune{pair[0]: '.', pair[1]: '-'}),
+			)
+		}
+	case len(symbols) == 3:
+		slices.Sort(symbols)
+		for i, separator := range symbols {
+			others := slices.Delete(slices.Clone(symbols), i, i+1)
+			for _, pair := range [][2]rune{{others[0], others[1]}, {others[1], others[0]}} {
+				add(
+					fmt.Sprintf("%c = dot, %c = dash, %c = letter separator", pair[0], pair[1], separator),
+					translateMorse(input, map[rune]rune{pair[0]: '.', pair[1]: '-', separator: ' '}),
+				)
+			}
+		}
+	}
+
+	if !standard && len(symbols) != 2 {
+		tokens := morseTokens(input)
+		var lengths []int
+		for _, token := range tokens {
+			if token.length > 0 && !slices.Contains(lengths, token.length) {
+				lengths = append(lengths, token.length)
+			}
+		}
+		slices.Sort(lengths)
+
+		for _, threshold := range lengths[:max(len(lengths)-1, 0)] {
+			add(
+				fmt.Sprintf("tokens of length ≤ %d = dot, longer tokens = dash", threshold),
+				translateMorseTokens(tokens, threshold, '.', '-'),
+			)
+			add(
+				fmt.Sprintf("tokens of length ≤ %d = dash, longer tokens = dot", threshold),
+				translateMorseTokens(tokens, threshold, '-', '.'),
+			)
+		}
+	}
+
+	return mappings
+}
+
+// FromMorseVariants decodes the input in the same way as FromMorse for each of the mappings returned by
+// MorseMappings, and returns the groups of words found by each mapping that produced any.
+func FromMorseVariants(ctx context.Context, checker Dictionary, input string) ([]MorseGroup, error) {
+	var groups []MorseGroup
+	for _, mapping := range MorseMappings(input) {
+		words := slices.Collect(FromMorseSeq(ctx, checker, mapping.Signals))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(words) > 0 {
+			groups = append(groups, MorseGroup{Mapping: mapping, Words: words})
+		}
+	}
+	return groups, nil
+}
+
+// morseSymbolsIn returns the distinct symbols used in morse-like input, ignoring separators and treating alternative
+// forms of dots and dashes as the standard ones. Symbols are returned in the order they first occur.
+func morseSymbolsIn(input string) []rune {
+	var symbols []rune
+	for _, r := range strings.ToLower(input) {
+		if r = normaliseMorseSymbol(r); r != 0 && !slices.Contains(symbols, r) {
+			symbols = append(symbols, r)
+		}
+	}
+	return symbols
+}
+
+// isStandardMorse determines whether the given symbols are only dots and dashes.
+func isStandardMorse(symbols []rune) bool {
+	return len(symbols) > 0 && !slices.ContainsFunc(symbols, func(r rune) bool {
+		return r != '.' && r != '-'
+	})
+}
+
+// normaliseMorseSymbol maps alternative forms of dots and dashes to '.' and '-', and returns 0 for separators.
+func normaliseMorseSymbol(r rune) rune {
+	switch {
+	case r == '·' || r == '•' || r == '∙':
+		return '.'
+	case r == '_' || r == '−' || r == '–' || r == '—':
+		return '-'
+	case r == '/' || r == '|' || unicode.IsSpace(r):
+		return 0
+	default:
+		return r
+	}
+}
+
+// translateMorse replaces each symbol in the input according to the given mapping, leaving separators intact.
+func translateMorse(input string, mapping map[rune]rune) string {
+	b := &strings.Builder{}
+	for _, r := range strings.ToLower(input) {
+		if symbol := normaliseMorseSymbol(r); symbol == 0 {
+			b.WriteRune(r)
+		} else if replacement, ok := mapping[symbol]; ok {
+			b.WriteRune(replacement)
+		}
+	}
+	return b.String()
+}
+
+// morseToken is a whitespace-separated token of input that represents a single morse signal, or an explicit word
+// separator.
+type morseToken struct {
+	// length is the number of characters in the token, ignoring punctuation.
+	length int
+	// gap ranks the size of the whitespace before the token: 0 if there was none, 1 for the smallest gap in the
+	// input, 2 for the next smallest, and so on.
+	gap       int
+	separator bool
+}
+
+// morseTokens splits the input into tokens, ranking the size of the whitespace before each of them. Tokens consisting
+// only of punctuation are ignored.
+func morseTokens(input string) []morseToken {
+	var (
+		tokens  []morseToken
+		current *morseToken
+		gap     int
+	)
+
+	for _, r := range input {
+		switch {
+		case unicode.IsSpace(r):
+			current = nil
+			gap++
+		case r == '/' || r == '|':
+			current = nil
+			tokens = append(tokens, morseToken{separator: true})
+		default:
+			if current == nil {
+				if len(tokens) == 0 || tokens[len(tokens)-1].separator {
+					gap = 0
+				}
+				tokens = append(tokens, morseToken{gap: gap})
+				current = &tokens[len(tokens)-1]
+				gap = 0
+			}
+			if !unicode.IsPunct(r) {
+				current.length++
+			}
+		}
+	}
+
+	var gaps []int
+	for _, token := range tokens {
+		if token.gap > 0 && !slices.Contains(gaps, token.gap) {
+			gaps = append(gaps, token.gap)
+		}
+	}
+	slices.Sort(gaps)
+
+	for i := range tokens {
+		if tokens[i].gap > 0 {
+			tokens[i].gap = slices.Index(gaps, tokens[i].gap) + 1
+		}
+	}
+
+	return slices.DeleteFunc(tokens, func(token morseToken) bool {
+		return !token.separator && token.length == 0
+	})
+}
+
+// translateMorseTokens converts tokens to morse signals, using the short signal for tokens up to the threshold length
+// and the long signal for the rest. If the gaps between tokens vary, the second smallest gaps separate letters and
+// any larger gaps separate words.
+func translateMorseTokens(tokens []morseToken, threshold int, short, long byte) string {
+	b := &strings.Builder{}
+	for _, token := range tokens {
+		switch {
+		case token.separator:
+			b.WriteByte('/')
+			continue
+		case token.gap == 2:
+			b.WriteByte(' ')
+		case token.gap > 2:
+			b.WriteByte('/')
+		}
+
+		if token.length <= threshold {
+			b.WriteByte(short)
+		} else {
+			b.WriteByte(long)
+		}
+	}
+	return b.String()
 }
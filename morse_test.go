@@ -1,7 +1,10 @@
 package kowalski
 
 import (
+	"context"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -15,6 +18,10 @@ func TestFromMorse(t *testing.T) {
 		{"bar", "-....-.-.", []string{"bar"}},
 		{"baz", "-... .- --..", []string{"baz"}},
 		{"quux", "--.-..-..--..-", []string{"quux"}},
+		{"partial spacing", "-... .-.-.", []string{"bar"}},
+		{"word separators", "..-.------ / -....-.-.", []string{"foo bar"}},
+		{"fully spaced words", "..-. --- --- / -... .- .-.", []string{"foo bar"}},
+		{"alternative symbols", "··-·——————", []string{"foo"}},
 	}
 
 	for _, tt := range tests {
@@ -25,3 +32,193 @@ func TestFromMorse(t *testing.T) {
 		})
 	}
 }
+
+func TestFromMorseSpacing(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("eat\nate\ntea\nteat\n"))
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"unspaced", "..--", []string{"eat"}},
+		{"fully spaced", ". .- -", []string{"eat"}},
+		{"spaced runs are single letters", ".. --", nil},
+		{"partially spaced", "- ..--", []string{"teat"}},
+		{"boundary between letters respected", "-. .--", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromMorse(list, tt.query)
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromMorse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiFromMorse(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("foo\nbar\nfoobar\nba\nr\n"))
+
+	tests := []struct {
+		name    string
+		query   string
+		options MultiWordOptions
+		want    []string
+	}{
+		{"split words", "..-.-------....-.-.", MultiWordOptions{}, []string{"foo bar", "foobar"}},
+		{"one letter words", "..-.-------....-.-.", MultiWordOptions{MinWordLength: 1}, []string{"foo ba r", "foo bar", "foobar"}},
+		{"word count", "..-.-------....-.-.", MultiWordOptions{MinWords: 2}, []string{"foo bar"}},
+		{"separators respected", "..-.------ / -....-.-.", MultiWordOptions{}, []string{"foo bar"}},
+		{"enumeration", "..-.-------....-.-.", MultiWordOptions{Enumeration: mustParseEnumeration("(6)")}, []string{"foobar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := MultiFromMorse(context.Background(), list, tt.query, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MultiFromMorse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMorse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"word", "SOS", "... --- ...", false},
+		{"words", "foo bar", "..-. --- --- / -... .- .-.", false},
+		{"digits and punctuation", "r2-d2?", ".-. ..--- -....- -.. ..--- ..--..", false},
+		{"unsupported character", "café", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMorse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToMorse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToMorse() = %q, want %q", got, tt.want)
+			}
+
+			if !tt.wantErr {
+				if decoded, err := DecodeMorse(got); err != nil || decoded != strings.ToLower(tt.input) {
+					t.Errorf("DecodeMorse() = %q, %v, want %q", decoded, err, strings.ToLower(tt.input))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeMorseInvalid(t *testing.T) {
+	if _, err := DecodeMorse("........"); err == nil {
+		t.Errorf("DecodeMorse() with invalid code returned no error")
+	}
+}
+
+func TestMorseMappings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []MorseMapping
+	}{
+		{"standard", "-.-. .- -", []MorseMapping{
+			{"standard", "-.-. .- -"},
+			{"dots and dashes swapped", ".-.- -. ."},
+		}},
+		{"binary", "1010 01/1", []MorseMapping{
+			{"0 = dot, 1 = dash", "-.-. .-/-"},
+			{"1 = dot, 0 = dash", ".-.- -./."},
+		}},
+		{"letter separator", "0102", []MorseMapping{
+			{"1 = dot, 2 = dash, 0 = letter separator", ". -"},
+			{"2 = dot, 1 = dash, 0 = letter separator", "- ."},
+			{"0 = dot, 2 = dash, 1 = letter separator", ". .-"},
+			{"2 = dot, 0 = dash, 1 = letter separator", "- -."},
+			{"0 = dot, 1 = dash, 2 = letter separator", ".-."},
+			{"1 = dot, 0 = dash, 2 = letter separator", "-.-"},
+		}},
+		{"visual", "▄▄▄ ▄ ▄▄▄ ▄   ▄ ▄▄▄", []MorseMapping{
+			{"tokens of length ≤ 1 = dot, longer tokens = dash", "-.-. .-"},
+			{"tokens of length ≤ 1 = dash, longer tokens = dot", ".-.- -."},
+		}},
+		{"words", "Cats, and dogs / a mouse", []MorseMapping{
+			{"tokens of length ≤ 1 = dot, longer tokens = dash", "---/.-"},
+			{"tokens of length ≤ 1 = dash, longer tokens = dot", ".../-."},
+			{"tokens of length ≤ 3 = dot, longer tokens = dash", "-.-/.-"},
+			{"tokens of length ≤ 3 = dash, longer tokens = dot", ".-./-."},
+			{"tokens of length ≤ 4 = dot, longer tokens = dash", ".../.-"},
+			{"tokens of length ≤ 4 = dash, longer tokens = dot", "---/-."},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MorseMappings(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MorseMappings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMorseVariants(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("cat\nact\ntea\n"))
+
+	got, err := FromMorseVariants(context.Background(), list, "0101 10 0")
+	if err != nil {
+		t.Fatalf("FromMorseVariants() error = %v", err)
+	}
+
+	want := []MorseGroup{{
+		Mapping: MorseMapping{Description: "1 = dot, 0 = dash", Signals: "-.-. .- -"},
+		Words:   []string{"cat"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromMorseVariants() = %v, want %v", got, want)
+	}
+}
+
+func TestMultiplexFromMorseVariants(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("cat\nact\ntea\n"))
+	backup, _ := CreateWordList(strings.NewReader("cat\naane\n"))
+
+	got, err := MultiplexFromMorseVariants(context.Background(), []Dictionary{list, backup}, "0101 10 0", Dedupe)
+	if err != nil {
+		t.Fatalf("MultiplexFromMorseVariants() error = %v", err)
+	}
+
+	want := []MultiplexMorseGroup{
+		{Mapping: MorseMapping{Description: "0 = dot, 1 = dash", Signals: ".-.- -. ."}, Words: [][]string{nil, {"aane"}}},
+		{Mapping: MorseMapping{Description: "1 = dot, 0 = dash", Signals: "-.-. .- -"}, Words: [][]string{{"cat"}, nil}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiplexFromMorseVariants() = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MultiplexFromMorseVariants(ctx, []Dictionary{list}, "0101 10 0"); err == nil {
+		t.Errorf("MultiplexFromMorseVariants() returned no error for a cancelled context")
+	}
+}
+
+func TestAnalyse_Morse(t *testing.T) {
+	results, _ := Analyse(context.Background(), testChecker, "..-. --- --- / -... .- .-.", WithAnalysers("morse"))
+	if len(results) == 0 || results[len(results)-1].Output != "foo bar" {
+		t.Errorf("Analyse() = %+v, want a morse decoding of foo bar", results)
+	}
+
+	// "noon" could be read as ".--." (an), but is far more likely to be a word
+	list, _ := CreateWordList(strings.NewReader("noon\nan\n"))
+	for _, input := range []string{"noon", "noon noon", "Noon"} {
+		if results, _ := Analyse(context.Background(), list, input, WithAnalysers("morse")); len(results) > 0 {
+			t.Errorf("Analyse(%q) = %+v, want no morse findings", input, results)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package kowalski
 
 import (
 	"context"
+	"fmt"
+	"iter"
+	"slices"
 	"sync"
 )
 
@@ -9,6 +12,8 @@ type MultiplexOption func(*multiplexOptions)
 
 type multiplexOptions struct {
 	dedupe bool
+	ranked bool
+	limit  int
 }
 
 // Dedupe removes duplicate entries from multiplexed results. That is, if the first checker provides words A, B and C,
@@ -17,72 +22,168 @@ func Dedupe(options *multiplexOptions) {
 	options.dedupe = true
 }
 
+// Ranked orders each checker's results from most to least common (see Rank), instead of the order returned by the
+// solver. It has no effect on checkers that do not implement Weighted. Ranking is applied before deduplication.
+func Ranked(options *multiplexOptions) {
+	options.ranked = true
+}
+
+// Limit restricts the number of results returned from each checker. Where possible the solver is stopped as soon as
+// enough results have been found, which can dramatically reduce the time taken by broad searches. When combined with
+// Ranked, every result from a Weighted checker is found and ranked before the limit is applied, so the most common
+// words are kept. Limits are applied before deduplication.
+func Limit(n int) MultiplexOption {
+	return func(options *multiplexOptions) {
+		options.limit = n
+	}
+}
+
 // MultiplexMatch performs the Match operation over a number of different checkers.
-func MultiplexMatch(ctx context.Context, checkers []*SpellChecker, pattern string, opts ...MultiplexOption) ([][]string, error) {
-	return multiplexWithErrors(checkers, func(checker *SpellChecker) ([]string, error) {
-		return Match(ctx, checker, pattern)
+func MultiplexMatch(ctx context.Context, checkers []Dictionary, pattern string, opts ...MultiplexOption) ([][]string, error) {
+	if _, err := CompilePattern(pattern); err != nil {
+		return nil, err
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return MatchSeq(ctx, checker, pattern)
 	}, opts)
 }
 
 // MultiplexMultiMatch performs the MultiMatch operation over a number of different checkers.
-func MultiplexMultiMatch(ctx context.Context, checkers []*SpellChecker, pattern string, opts ...MultiplexOption) ([][]string, error) {
-	return multiplexWithErrors(checkers, func(checker *SpellChecker) ([]string, error) {
-		return MultiMatch(ctx, checker, pattern)
+func MultiplexMultiMatch(ctx context.Context, checkers []Dictionary, pattern string, options MultiWordOptions, opts ...MultiplexOption) ([][]string, error) {
+	if _, err := compileMultiPattern(pattern, options); err != nil {
+		return nil, err
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return MultiMatchSeq(ctx, checker, pattern, options)
 	}, opts)
 }
 
 // MultiplexAnagram performs the Anagram operation over a number of different checkers.
-func MultiplexAnagram(ctx context.Context, checkers []*SpellChecker, pattern string, opts ...MultiplexOption) ([][]string, error) {
-	return multiplexWithErrors(checkers, func(checker *SpellChecker) ([]string, error) {
-		return Anagram(ctx, checker, pattern)
+func MultiplexAnagram(ctx context.Context, checkers []Dictionary, pattern string, opts ...MultiplexOption) ([][]string, error) {
+	if _, err := ParseAnagramExpression(pattern); err != nil {
+		return nil, err
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return AnagramSeq(ctx, checker, pattern)
+	}, opts)
+}
+
+// MultiplexSubAnagram performs the SubAnagram operation over a number of different checkers.
+func MultiplexSubAnagram(ctx context.Context, checkers []Dictionary, letters string, options SubAnagramOptions, opts ...MultiplexOption) ([][]string, error) {
+	if _, err := ParseAnagramExpression(letters); err != nil {
+		return nil, err
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return SubAnagramSeq(ctx, checker, letters, options)
 	}, opts)
 }
 
 // MultiplexMultiAnagram performs the MultiAnagram operation over a number of different checkers.
-func MultiplexMultiAnagram(ctx context.Context, checkers []*SpellChecker, pattern string, opts ...MultiplexOption) ([][]string, error) {
-	return multiplexWithErrors(checkers, func(checker *SpellChecker) ([]string, error) {
-		return MultiAnagram(ctx, checker, pattern)
+func MultiplexMultiAnagram(ctx context.Context, checkers []Dictionary, pattern string, options MultiWordOptions, opts ...MultiplexOption) ([][]string, error) {
+	if _, err := parseMultiAnagram(pattern, options); err != nil {
+		return nil, err
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return MultiAnagramSeq(ctx, checker, pattern, options)
 	}, opts)
 }
 
 // MultiplexFindWords performs the FindWords operation over a number of different checkers.
-func MultiplexFindWords(checkers []*SpellChecker, pattern string, opts ...MultiplexOption) [][]string {
-	return multiplex(checkers, func(checker *SpellChecker) []string {
+func MultiplexFindWords(checkers []Dictionary, pattern string, opts ...MultiplexOption) [][]string {
+	return multiplex(checkers, func(checker Dictionary) []string {
 		return FindWords(checker, pattern)
 	}, opts)
 }
 
 // MultiplexFromMorse performs the FromMorse operation over a number of different checkers.
-func MultiplexFromMorse(checkers []*SpellChecker, pattern string, opts ...MultiplexOption) [][]string {
-	return multiplex(checkers, func(checker *SpellChecker) []string {
-		return FromMorse(checker, pattern)
+func MultiplexFromMorse(ctx context.Context, checkers []Dictionary, pattern string, opts ...MultiplexOption) ([][]string, error) {
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return FromMorseSeq(ctx, checker, pattern)
+	}, opts)
+}
+
+// MultiplexMorseGroup is a set of words decoded from an input by each of a number of different checkers, using a
+// particular mapping.
+type MultiplexMorseGroup struct {
+	Mapping MorseMapping `json:"mapping"`
+	Words   [][]string   `json:"words"`
+}
+
+// MultiplexFromMorseVariants performs the FromMorseVariants operation over a number of different checkers. Options
+// are applied to the words found using each mapping separately, and mappings that don't produce any words from any of
+// the checkers are omitted.
+func MultiplexFromMorseVariants(ctx context.Context, checkers []Dictionary, input string, opts ...MultiplexOption) ([]MultiplexMorseGroup, error) {
+	var groups []MultiplexMorseGroup
+	for _, mapping := range MorseMappings(input) {
+		words, err := multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+			return FromMorseSeq(ctx, checker, mapping.Signals)
+		}, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(words, func(w []string) bool { return len(w) > 0 }) {
+			groups = append(groups, MultiplexMorseGroup{Mapping: mapping, Words: words})
+		}
+	}
+	return groups, nil
+}
+
+// MultiplexMultiFromMorse performs the MultiFromMorse operation over a number of different checkers.
+func MultiplexMultiFromMorse(ctx context.Context, checkers []Dictionary, input string, options MultiWordOptions, opts ...MultiplexOption) ([][]string, error) {
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return MultiFromMorseSeq(ctx, checker, input, options)
 	}, opts)
 }
 
 // MultiplexOffByOne performs the OffByOne operation over a number of different checkers.
-func MultiplexOffByOne(ctx context.Context, checkers []*SpellChecker, pattern string, opts ...MultiplexOption) ([][]string, error) {
-	return multiplexWithErrors(checkers, func(checker *SpellChecker) ([]string, error) {
+func MultiplexOffByOne(ctx context.Context, checkers []Dictionary, pattern string, opts ...MultiplexOption) ([][]string, error) {
+	return multiplexWithErrors(checkers, func(checker Dictionary) ([]string, error) {
 		return OffByOne(ctx, checker, pattern)
 	}, opts)
 }
 
+// MultiplexWordLadder performs the WordLadder operation over a number of different checkers, returning the ladder
+// found using each one. Multiplex options aren't accepted, as ranking or deduplicating a ladder would break it.
+func MultiplexWordLadder(ctx context.Context, checkers []Dictionary, from, to string, options LadderOptions) ([][]string, error) {
+	return multiplexWithErrors(checkers, func(checker Dictionary) ([]string, error) {
+		return WordLadder(ctx, checker, from, to, options)
+	}, nil)
+}
+
+// MultiplexFuzzy performs the Fuzzy operation over a number of different checkers.
+func MultiplexFuzzy(ctx context.Context, checkers []Dictionary, word string, maxDistance int, opts ...MultiplexOption) ([][]string, error) {
+	if maxDistance < 0 {
+		return nil, fmt.Errorf("invalid edit distance: %d", maxDistance)
+	}
+
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return FuzzySeq(ctx, checker, word, maxDistance)
+	}, opts)
+}
+
 // MultiplexFromT9 performs the FromT9 operation over a number of different checkers.
-func MultiplexFromT9(checkers []*SpellChecker, pattern string, opts ...MultiplexOption) [][]string {
-	return multiplex(checkers, func(checker *SpellChecker) []string {
-		return FromT9(checker, pattern)
+func MultiplexFromT9(ctx context.Context, checkers []Dictionary, pattern string, options T9Options, opts ...MultiplexOption) ([][]string, error) {
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return FromT9Seq(ctx, checker, pattern, options)
 	}, opts)
 }
 
 // MultiplexWordSearch performs the WordSearch operation over a number of different checkers.
-func MultiplexWordSearch(checkers []*SpellChecker, pattern []string, opts ...MultiplexOption) [][]string {
-	return multiplex(checkers, func(checker *SpellChecker) []string {
-		return WordSearch(checker, pattern)
+func MultiplexWordSearch(ctx context.Context, checkers []Dictionary, pattern []string, opts ...MultiplexOption) ([][]string, error) {
+	return multiplexSeq(ctx, checkers, func(checker Dictionary) iter.Seq[string] {
+		return WordSearchSeq(ctx, checker, pattern)
 	}, opts)
 }
 
 // MultiplexCheckWords performs the CheckWords operation over a number of different checkers.
 // Returns results for each checker separately.
-func MultiplexCheckWords(checkers []*SpellChecker, input string) [][][]WordCheckResult {
+func MultiplexCheckWords(checkers []Dictionary, input string) [][][]WordCheckResult {
 	results := make([][][]WordCheckResult, len(checkers))
 	wg := &sync.WaitGroup{}
 
@@ -98,11 +199,8 @@ func MultiplexCheckWords(checkers []*SpellChecker, input string) [][][]WordCheck
 	return results
 }
 
-func multiplex(checkers []*SpellChecker, f func(checker *SpellChecker) []string, opts []MultiplexOption) [][]string {
-	o := &multiplexOptions{}
-	for i := range opts {
-		opts[i](o)
-	}
+func multiplex(checkers []Dictionary, f func(checker Dictionary) []string, opts []MultiplexOption) [][]string {
+	o := applyMultiplexOptions(opts)
 
 	res := make([][]string, len(checkers))
 	wg := &sync.WaitGroup{}
@@ -117,17 +215,38 @@ func multiplex(checkers []*SpellChecker, f func(checker *SpellChecker) []string,
 
 	wg.Wait()
 
+	for i := range res {
+		if o.ranked {
+			res[i] = rankResults(checkers[i], res[i])
+		}
+
+		if o.limit > 0 && len(res[i]) > o.limit {
+			res[i] = res[i][:o.limit]
+		}
+	}
+
 	if o.dedupe {
 		return dedupe(res)
 	}
 	return res
 }
 
-func multiplexWithErrors(checkers []*SpellChecker, f func(checker *SpellChecker) ([]string, error), opts []MultiplexOption) ([][]string, error) {
-	o := &multiplexOptions{}
-	for i := range opts {
-		opts[i](o)
-	}
+// multiplexSeq collects the results of a streaming solver over a number of different checkers. If a limit is set,
+// each solver is stopped once it has produced enough results, unless its results are going to be ranked by frequency.
+func multiplexSeq(ctx context.Context, checkers []Dictionary, f func(checker Dictionary) iter.Seq[string], opts []MultiplexOption) ([][]string, error) {
+	o := applyMultiplexOptions(opts)
+
+	return multiplexWithErrors(checkers, func(checker Dictionary) ([]string, error) {
+		limit := o.limit
+		if _, ok := checker.(Weighted); ok && o.ranked {
+			limit = 0
+		}
+		return collectN(ctx, f(checker), limit)
+	}, opts)
+}
+
+func multiplexWithErrors(checkers []Dictionary, f func(checker Dictionary) ([]string, error), opts []MultiplexOption) ([][]string, error) {
+	o := applyMultiplexOptions(opts)
 
 	res := make([][]string, len(checkers))
 	errs := make([]error, len(checkers))
@@ -149,12 +268,30 @@ func multiplexWithErrors(checkers []*SpellChecker, f func(checker *SpellChecker)
 		}
 	}
 
+	for i := range res {
+		if o.ranked {
+			res[i] = rankResults(checkers[i], res[i])
+		}
+
+		if o.limit > 0 && len(res[i]) > o.limit {
+			res[i] = res[i][:o.limit]
+		}
+	}
+
 	if o.dedupe {
 		return dedupe(res), nil
 	}
 	return res, nil
 }
 
+func applyMultiplexOptions(opts []MultiplexOption) *multiplexOptions {
+	o := &multiplexOptions{}
+	for i := range opts {
+		opts[i](o)
+	}
+	return o
+}
+
 func dedupe(data [][]string) [][]string {
 	res := make([][]string, len(data))
 
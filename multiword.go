@@ -0,0 +1,212 @@
+package kowalski
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MultiWordOptions constrains the results of multi-word solvers such as MultiAnagram and MultiMatch. Zero values
+// leave the corresponding property unconstrained, except for MinWordLength where each solver applies its own default.
+type MultiWordOptions struct {
+	// MinWordLength is the minimum length of each word in a result.
+	MinWordLength int
+	// MaxWordLength is the maximum length of each word in a result.
+	MaxWordLength int
+	// MinWords is the minimum number of words in a result.
+	MinWords int
+	// MaxWords is the maximum number of words in a result.
+	MaxWords int
+	// Required lists words that must appear in every result.
+	Required []string
+	// Enumeration fixes the number and lengths of the words in a result, as with a crossword clue. If set, the
+	// word length and word count constraints are ignored, and results are joined using the enumeration's
+	// separators.
+	Enumeration Enumeration
+}
+
+// ParseMultiWordOptions splits options in the form "key=value" from the rest of the input, returning the remaining
+// fields joined together along with the parsed options. The supported keys are:
+//
+//   - min: the minimum length of each word
+//   - max: the maximum length of each word
+//   - words: the exact number of words
+//   - minwords: the minimum number of words
+//   - maxwords: the maximum number of words
+//   - with: a word that must appear in each result (may be repeated)
+//
+// An enumeration in parentheses, such as "(3,4)", may also be given (see ParseEnumeration).
+//
+// For example, "letters min=3 words=2" returns "letters" with MinWordLength 3 and MinWords and MaxWords both 2.
+func ParseMultiWordOptions(input string) (string, MultiWordOptions, error) {
+	rest, options, err := parseMultiWordOptions(input)
+	if err != nil {
+		return "", MultiWordOptions{}, err
+	}
+	return strings.Join(rest, ""), options, nil
+}
+
+// parseMultiWordOptions implements ParseMultiWordOptions, returning the fields that weren't options separately.
+func parseMultiWordOptions(input string) ([]string, MultiWordOptions, error) {
+	var (
+		options MultiWordOptions
+		rest    []string
+	)
+
+	for _, field := range strings.Fields(collapseEnumerations(input)) {
+		if start := strings.IndexByte(field, '('); start != -1 && strings.HasSuffix(field, ")") {
+			if options.Enumeration.Words() > 0 {
+				return nil, MultiWordOptions{}, fmt.Errorf("only one enumeration may be given")
+			}
+
+			enumeration, err := ParseEnumeration(field[start:])
+			if err != nil {
+				return nil, MultiWordOptions{}, err
+			}
+
+			options.Enumeration = enumeration
+			if start > 0 {
+				rest = append(rest, field[:start])
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
+
+		key = strings.ToLower(key)
+		if key == "with" {
+			if value == "" {
+				return nil, MultiWordOptions{}, fmt.Errorf("option %s requires a word", key)
+			}
+			options.Required = append(options.Required, strings.ToLower(value))
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, MultiWordOptions{}, fmt.Errorf("option %s requires a non-negative number, got %q", key, value)
+		}
+
+		switch key {
+		case "min":
+			options.MinWordLength = n
+		case "max":
+			options.MaxWordLength = n
+		case "words":
+			options.MinWords = n
+			options.MaxWords = n
+		case "minwords":
+			options.MinWords = n
+		case "maxwords":
+			options.MaxWords = n
+		default:
+			return nil, MultiWordOptions{}, fmt.Errorf("unknown option: %s", key)
+		}
+	}
+
+	return rest, options, nil
+}
+
+// collapseEnumerations removes spaces within parentheses, so that enumerations such as "(3, 4)" aren't split into
+// separate fields. A space between two numbers separates words, as in "(3 4)", so is replaced with a comma.
+func collapseEnumerations(input string) string {
+	b := &strings.Builder{}
+	depth := 0
+	var last rune
+	spaced := false
+	for _, r := range input {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth = max(depth-1, 0)
+		case depth > 0 && r == ' ':
+			spaced = true
+			continue
+		case spaced && unicode.IsDigit(last) && unicode.IsDigit(r):
+			b.WriteRune(',')
+		}
+		b.WriteRune(r)
+		last = r
+		spaced = false
+	}
+	return b.String()
+}
+
+// checkEnumeration returns an error if the options include an enumeration, and results made from the input (which has
+// the given number of letters, or at least that many if variable is set) can't fit it.
+func (o MultiWordOptions) checkEnumeration(input string, letters int, variable bool) error {
+	if !o.enumerated() {
+		return nil
+	}
+
+	if n := o.Enumeration.Len(); letters > n || (!variable && letters != n) {
+		return fmt.Errorf("%q doesn't fit the enumeration %s, which has %d letters", input, o.Enumeration, n)
+	}
+	return nil
+}
+
+// enumerated determines whether the options include an enumeration.
+func (o MultiWordOptions) enumerated() bool {
+	return o.Enumeration.Words() > 0
+}
+
+// allowsWord determines whether a complete word of the given length may appear at the given index in a result.
+func (o MultiWordOptions) allowsWord(index, length int) bool {
+	if o.enumerated() {
+		return index < len(o.Enumeration.lengths) && length == o.Enumeration.lengths[index]
+	}
+	return length >= o.MinWordLength && (o.MaxWordLength == 0 || length <= o.MaxWordLength)
+}
+
+// allowsPrefix determines whether the word at the given index in a result may be at least the given length.
+func (o MultiWordOptions) allowsPrefix(index, length int) bool {
+	if o.enumerated() {
+		return index < len(o.Enumeration.lengths) && length <= o.Enumeration.lengths[index]
+	}
+	return o.MaxWordLength == 0 || length <= o.MaxWordLength
+}
+
+// allowsMoreWords determines whether a result that already has the given number of words may have another.
+func (o MultiWordOptions) allowsMoreWords(count int) bool {
+	if o.enumerated() {
+		return count < len(o.Enumeration.lengths)
+	}
+	return o.MaxWords == 0 || count < o.MaxWords
+}
+
+// accepts determines whether a complete result satisfies the word count and required word constraints. Word lengths
+// are checked while searching, so aren't considered here.
+func (o MultiWordOptions) accepts(words []string) bool {
+	if o.enumerated() {
+		if len(words) != len(o.Enumeration.lengths) {
+			return false
+		}
+	} else if len(words) < o.MinWords || !o.allowsMoreWords(len(words)-1) {
+		return false
+	}
+
+	remaining := slices.Clone(words)
+	for _, required := range o.Required {
+		i := slices.Index(remaining, required)
+		if i == -1 {
+			return false
+		}
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+	return true
+}
+
+// join combines the words of a result into a single string, using the enumeration's separators if there is one.
+func (o MultiWordOptions) join(words []string) string {
+	if o.enumerated() {
+		return o.Enumeration.join(words)
+	}
+	return strings.Join(words, " ")
+}
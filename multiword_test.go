@@ -0,0 +1,56 @@
+package kowalski
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMultiWordOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantInput string
+		want      MultiWordOptions
+		wantErr   bool
+	}{
+		{"no options", "letters", "letters", MultiWordOptions{}, false},
+		{"word lengths", "letters min=3 max=5", "letters", MultiWordOptions{MinWordLength: 3, MaxWordLength: 5}, false},
+		{"exact word count", "letters words=2", "letters", MultiWordOptions{MinWords: 2, MaxWords: 2}, false},
+		{"word count bounds", "minwords=2 letters maxwords=4", "letters", MultiWordOptions{MinWords: 2, MaxWords: 4}, false},
+		{"required words", "letters with=cat with=DOG", "letters", MultiWordOptions{Required: []string{"cat", "dog"}}, false},
+		{"split input", "abc def min=2", "abcdef", MultiWordOptions{MinWordLength: 2}, false},
+		{"enumeration", "letters (3,4)", "letters", MultiWordOptions{Enumeration: mustParseEnumeration("(3,4)")}, false},
+		{"enumeration with spaces", "letters (3, 4)", "letters", MultiWordOptions{Enumeration: mustParseEnumeration("(3,4)")}, false},
+		{"space separated enumeration", "letters (3 4)", "letters", MultiWordOptions{Enumeration: mustParseEnumeration("(3,4)")}, false},
+		{"attached enumeration", "c?t????(3-4)", "c?t????", MultiWordOptions{Enumeration: mustParseEnumeration("(3-4)")}, false},
+		{"multiple enumerations", "letters (3) (4)", "", MultiWordOptions{}, true},
+		{"invalid enumeration", "letters (3,x)", "", MultiWordOptions{}, true},
+		{"unknown option", "letters foo=2", "", MultiWordOptions{}, true},
+		{"invalid number", "letters min=x", "", MultiWordOptions{}, true},
+		{"negative number", "letters min=-1", "", MultiWordOptions{}, true},
+		{"empty required word", "letters with=", "", MultiWordOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, got, err := ParseMultiWordOptions(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMultiWordOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if input != tt.wantInput {
+				t.Errorf("ParseMultiWordOptions() input = %q, want %q", input, tt.wantInput)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMultiWordOptions() options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustParseEnumeration(input string) Enumeration {
+	e, err := ParseEnumeration(input)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
@@ -0,0 +1,257 @@
+package kowalski
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// NGrams is a language model built from the frequencies of every sequence of n letters (an n-gram) in a corpus
+// of English text. Quadgrams (n = 4) give a good balance between accuracy and size. Characters other than letters
+// are ignored, so n-grams span word boundaries and the model works on text without spaces.
+type NGrams struct {
+	info ModelInfo
+	n    int
+	// counts contains the number of times each n-gram occurred in the corpus, indexed by its letters read as a
+	// base-26 number. Only used when saving the model.
+	counts map[uint32]uint32
+	// logs contains the log10 probability of each n-gram, with unseen n-grams given a small floor probability.
+	logs []float32
+	// english and random are the mean log10 probability per n-gram of the training corpus and of uniformly random
+	// letters respectively, used to calibrate scores.
+	english, random float64
+}
+
+// minNGramLength and maxNGramLength bound the size of n-grams that can be modelled. Longer n-grams need too much
+// memory (26^n entries) and too much training text to be useful.
+const (
+	minNGramLength = 1
+	maxNGramLength = 5
+)
+
+// CreateNGrams counts the n-grams in the text read from the reader and creates a model from them. Only the
+// WithSource option has any effect.
+func CreateNGrams(reader io.Reader, n int, opts ...ModelOption) (*NGrams, error) {
+	if n < minNGramLength || n > maxNGramLength {
+		return nil, fmt.Errorf("n-gram length must be between %d and %d, got %d", minNGramLength, maxNGramLength, n)
+	}
+
+	o, err := applyModelOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		counts = make(map[uint32]uint32)
+		total  uint64
+		ngram  = newNGramWindow(n)
+		r      = bufio.NewReader(reader)
+	)
+
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if index, ok := ngram.add(b); ok {
+			counts[index]++
+			total++
+		}
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("no %d-letter sequences found in the training text", n)
+	}
+
+	info := ModelInfo{
+		Version:    modelFormatVersion,
+		Kind:       NGramsModel,
+		Source:     o.source,
+		NGramSize:  n,
+		NGramCount: total,
+		Created:    time.Now().UTC().Truncate(time.Second),
+	}
+	return newNGrams(info, counts, total), nil
+}
+
+// newNGrams calculates probabilities and calibration values from the n-gram counts.
+func newNGrams(info ModelInfo, counts map[uint32]uint32, total uint64) *NGrams {
+	m := &NGrams{
+		info:   info,
+		n:      info.NGramSize,
+		counts: counts,
+		logs:   make([]float32, ngramTableSize(info.NGramSize)),
+	}
+
+	floor := float32(math.Log10(0.01 / float64(total)))
+	for i := range m.logs {
+		m.logs[i] = floor
+	}
+
+	for index, count := range counts {
+		probability := float64(count) / float64(total)
+		m.logs[index] = float32(math.Log10(probability))
+		m.english += probability * math.Log10(probability)
+	}
+
+	for i := range m.logs {
+		m.random += float64(m.logs[i])
+	}
+	m.random /= float64(len(m.logs))
+
+	return m
+}
+
+// N returns the number of letters in each n-gram.
+func (m *NGrams) N() int {
+	return m.n
+}
+
+// Info returns details of how the model was built.
+func (m *NGrams) Info() ModelInfo {
+	return m.info
+}
+
+// LogLikelihood returns the mean log10 probability of the n-grams in the text. Higher (less negative) values
+// indicate text that is more like the training corpus. Text with fewer than n letters has no n-grams, and is given
+// the value expected for random letters.
+func (m *NGrams) LogLikelihood(text string) float64 {
+	var (
+		total float64
+		count int
+		ngram = newNGramWindow(m.n)
+	)
+
+	for i := 0; i < len(text); i++ {
+		if index, ok := ngram.add(text[i]); ok {
+			total += float64(m.logs[index])
+			count++
+		}
+	}
+
+	if count == 0 {
+		return m.random
+	}
+	return total / float64(count)
+}
+
+// Fitness returns the log likelihood of the text rescaled so that uniformly random letters score 0 and text typical
+// of the training corpus scores 1. Values outside that range are possible: very English-like text can score above 1,
+// and text made of unusual letter combinations below 0. Short texts give noisy results.
+func (m *NGrams) Fitness(text string) float64 {
+	return (m.LogLikelihood(text) - m.random) / (m.english - m.random)
+}
+
+// Score returns the text's Fitness limited to the range 0 to 1, so that the model can be used as a Scorer.
+func (m *NGrams) Score(text string) float64 {
+	return math.Min(math.Max(m.Fitness(text), 0), 1)
+}
+
+// SaveNGrams writes the model to the given writer, so that it can be restored with LoadNGrams.
+func SaveNGrams(writer io.Writer, model *NGrams) error {
+	body := &bytes.Buffer{}
+	_ = binary.Write(body, binary.BigEndian, uint32(len(model.counts)))
+	for _, index := range slices.Sorted(maps.Keys(model.counts)) {
+		_ = binary.Write(body, binary.BigEndian, index)
+		_ = binary.Write(body, binary.BigEndian, model.counts[index])
+	}
+	return encodeModel(writer, model.info, body.Bytes())
+}
+
+// LoadNGrams loads a model previously saved with SaveNGrams.
+func LoadNGrams(reader io.Reader) (*NGrams, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	info, body, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Kind != NGramsModel {
+		return nil, fmt.Errorf("%w: expected an n-gram model, got a %s", ErrUnknownModelFormat, info.Kind)
+	}
+
+	if info.NGramSize < minNGramLength || info.NGramSize > maxNGramLength {
+		return nil, fmt.Errorf("%w: invalid n-gram length %d", ErrCorruptModel, info.NGramSize)
+	}
+
+	if len(body) < 4 {
+		return nil, fmt.Errorf("%w: n-gram table is truncated", ErrCorruptModel)
+	}
+
+	entries := int(binary.BigEndian.Uint32(body))
+	if len(body) != 4+entries*8 {
+		return nil, fmt.Errorf("%w: n-gram table length mismatch", ErrCorruptModel)
+	}
+
+	var (
+		counts = make(map[uint32]uint32, entries)
+		total  uint64
+		limit  = uint32(ngramTableSize(info.NGramSize))
+	)
+	for i := range entries {
+		index := binary.BigEndian.Uint32(body[4+i*8:])
+		count := binary.BigEndian.Uint32(body[8+i*8:])
+		if index >= limit {
+			return nil, fmt.Errorf("%w: n-gram index %d out of range", ErrCorruptModel, index)
+		}
+		counts[index] += count
+		total += uint64(count)
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: n-gram table is empty", ErrCorruptModel)
+	}
+	return newNGrams(info, counts, total), nil
+}
+
+// ngramTableSize returns the number of possible n-grams of the given length.
+func ngramTableSize(n int) int {
+	size := 1
+	for range n {
+		size *= 26
+	}
+	return size
+}
+
+// ngramWindow tracks the last n letters of a text, ignoring other characters.
+type ngramWindow struct {
+	n, seen int
+	size    uint32
+	index   uint32
+}
+
+func newNGramWindow(n int) *ngramWindow {
+	return &ngramWindow{n: n, size: uint32(ngramTableSize(n))}
+}
+
+// add adds the character to the window, and returns the index of the n-gram it completes in a model's table (the
+// n-gram's letters read as a base-26 number). Returns false if the character isn't a letter, or not enough letters
+// have been seen yet.
+func (w *ngramWindow) add(b byte) (uint32, bool) {
+	var letter uint32
+	switch {
+	case b >= 'a' && b <= 'z':
+		letter = uint32(b - 'a')
+	case b >= 'A' && b <= 'Z':
+		letter = uint32(b - 'A')
+	default:
+		return 0, false
+	}
+
+	w.index = (w.index*26 + letter) % w.size
+	w.seen++
+	return w.index, w.seen >= w.n
+}
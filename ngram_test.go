@@ -0,0 +1,185 @@
+package kowalski
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+const ngramCorpus = "It is a truth universally acknowledged, that a single man in possession of a good fortune, must " +
+	"be in want of a wife. However little known the feelings or views of such a man may be on his first entering a " +
+	"neighbourhood, this truth is so well fixed in the minds of the surrounding families, that he is considered the " +
+	"rightful property of some one or other of their daughters."
+
+func TestCreateNGrams(t *testing.T) {
+	model, err := CreateNGrams(strings.NewReader(ngramCorpus), 3, WithSource("austen"))
+	if err != nil {
+		t.Fatalf("CreateNGrams() error = %v", err)
+	}
+
+	info := model.Info()
+	if info.Kind != NGramsModel || info.NGramSize != 3 || info.Source != "austen" || model.N() != 3 {
+		t.Errorf("CreateNGrams() info = %+v", info)
+	}
+
+	letters := len(nonLetterRegex.ReplaceAllString(strings.ToLower(ngramCorpus), ""))
+	if info.NGramCount != uint64(letters-2) {
+		t.Errorf("CreateNGrams() counted %d trigrams, want %d", info.NGramCount, letters-2)
+	}
+}
+
+func TestCreateNGramsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		corpus string
+		n      int
+	}{
+		{"too short", ngramCorpus, 0},
+		{"too long", ngramCorpus, 6},
+		{"no n-grams", "a, b", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CreateNGrams(strings.NewReader(tt.corpus), tt.n); err == nil {
+				t.Errorf("CreateNGrams() returned no error")
+			}
+		})
+	}
+}
+
+func TestNGrams_Fitness(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4)
+
+	if fitness := model.Fitness(ngramCorpus); math.Abs(fitness-1) > 0.001 {
+		t.Errorf("Fitness() = %f for the training text, want 1", fitness)
+	}
+
+	english := model.Fitness("the truth of the matter is")
+	random := model.Fitness("qzjxv wkpfm bqxzj")
+	if math.Abs(random) > 0.1 || english <= random {
+		t.Errorf("Fitness() = %f for English and %f for random letters, want English above random and random near 0", english, random)
+	}
+
+	if score := model.Score("qzjxv wkpfm bqxzj"); score != 0 {
+		t.Errorf("Score() = %f for random letters, want 0", score)
+	}
+
+	if score := model.Score("ab"); score != 0 {
+		t.Errorf("Score() = %f for text without any n-grams, want 0", score)
+	}
+}
+
+func TestSaveNGrams(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4, WithSource("austen"))
+
+	buffer := &bytes.Buffer{}
+	if err := SaveNGrams(buffer, model); err != nil {
+		t.Fatalf("SaveNGrams() error = %v", err)
+	}
+
+	loaded, err := LoadNGrams(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatalf("LoadNGrams() error = %v", err)
+	}
+
+	if loaded.Info() != model.Info() {
+		t.Errorf("LoadNGrams() info = %+v, want %+v", loaded.Info(), model.Info())
+	}
+
+	for _, field := range []string{"wordCount", "falsePositiveRate"} {
+		if bytes.Contains(buffer.Bytes(), []byte(field)) {
+			t.Errorf("SaveNGrams() wrote the word list field %q to the header", field)
+		}
+	}
+
+	for _, text := range []string{"the truth of the matter", "qzjxv wkpfm", ngramCorpus} {
+		if got, want := loaded.Fitness(text), model.Fitness(text); got != want {
+			t.Errorf("Fitness(%q) = %f after loading, want %f", text, got, want)
+		}
+	}
+
+	if _, err := LoadModel(bytes.NewReader(buffer.Bytes())); !errors.Is(err, ErrUnknownModelFormat) {
+		t.Errorf("LoadModel() error = %v for an n-gram model, want %v", err, ErrUnknownModelFormat)
+	}
+}
+
+func TestLoadNGramsErrors(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4)
+	buffer := &bytes.Buffer{}
+	_ = SaveNGrams(buffer, model)
+	valid := buffer.Bytes()
+
+	corrupt := append([]byte{}, valid...)
+	corrupt[len(corrupt)/2] ^= 0xff
+
+	list := &bytes.Buffer{}
+	_ = SaveWordList(list, testWordList)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"foreign file", []byte("this is not a model"), ErrUnknownModelFormat},
+		{"word list", list.Bytes(), ErrUnknownModelFormat},
+		{"corrupt file", corrupt, ErrCorruptModel},
+		{"truncated file", valid[:len(valid)-10], ErrCorruptModel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadNGrams(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("LoadNGrams() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadShippedNGrams(t *testing.T) {
+	f, err := os.Open("models/quadgrams.ng")
+	if err != nil {
+		t.Skipf("Unable to open shipped model: %v", err)
+	}
+	defer f.Close()
+
+	model, err := LoadNGrams(f)
+	if err != nil {
+		t.Fatalf("LoadNGrams() error = %v", err)
+	}
+
+	if score := model.Score("meet me at the station at midnight"); score < 0.8 {
+		t.Errorf("Shipped model scores English text %f, want at least 0.8", score)
+	}
+
+	if score := model.Score("phhw ph dw wkh vwdwlrq dw plgqljkw"); score > 0.2 {
+		t.Errorf("Shipped model scores Caesar-shifted text %f, want at most 0.2", score)
+	}
+}
+
+func TestAnalyse_WithScorer(t *testing.T) {
+	model, _ := CreateNGrams(strings.NewReader(ngramCorpus), 4)
+
+	input := "lw lv d wuxwk xqlyhuvdoob dfnqrzohgjhg"
+	results, err := Analyse(context.Background(), testChecker, input, WithScorer(model))
+	if err != nil {
+		t.Fatalf("Analyse() error = %v", err)
+	}
+	want := "Caesar shift of 23 might be English: it is a truth universally acknowledged"
+	for _, result := range results {
+		if strings.HasPrefix(result.Message, want) {
+			if result.Analyser != "caesar" || result.Category != CipherAnalysis || result.Confidence <= 0.5 {
+				t.Errorf("Analyse() = %+v, want a confident finding from the caesar analyser", result)
+			}
+			if result.Output != "it is a truth universally acknowledged" || result.Command != "shift "+input {
+				t.Errorf("Analyse() output = %q, command = %q", result.Output, result.Command)
+			}
+			return
+		}
+	}
+	t.Errorf("Analyse() = %v, want a result starting %q", results, want)
+}
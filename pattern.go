@@ -0,0 +1,311 @@
+package kowalski
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+const (
+	allLetters = uint32(1)<<26 - 1
+	vowels     = uint32(1)<<('a'-'a') | uint32(1)<<('e'-'a') | uint32(1)<<('i'-'a') | uint32(1)<<('o'-'a') | uint32(1)<<('u'-'a')
+	consonants = allLetters &^ vowels
+
+	// maxPatternMatchLength is the longest match that will be searched for when a pattern contains '*'. Bloom filter
+	// false positives mean a search for an unbounded run of letters might otherwise never finish.
+	maxPatternMatchLength = 45
+)
+
+// Pattern is a compiled word pattern. Patterns consist of lowercase letters, which must match exactly, and the
+// following special characters:
+//
+//   - '?' matches any single letter
+//   - '*' matches any run of letters, including an empty one
+//   - '#' matches any consonant
+//   - '@' matches any vowel
+//   - '[aeiou]' matches any of the letters in the brackets; ranges such as '[a-f]' are also supported
+//   - '[^xyz]' matches any letter except those in the brackets
+type Pattern struct {
+	source string
+	tokens []patternToken
+	fixed  int
+}
+
+type patternToken struct {
+	// letters is a bitmask of the letters matched by the token, with bit 0 representing 'a'.
+	letters uint32
+	// repeat indicates the token matches any number of letters (including none) instead of exactly one.
+	repeat bool
+}
+
+// patternState tracks which tokens a pattern could be positioned at after consuming some letters. Bit i is set if
+// the next letter could be matched by token i; bit len(tokens) is set if the pattern could be complete.
+type patternState []uint64
+
+// CompilePattern parses the given pattern so it can be matched against words. Patterns are case-insensitive.
+func CompilePattern(pattern string) (*Pattern, error) {
+	p := &Pattern{source: pattern}
+	input := strings.ToLower(pattern)
+
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c >= 'a' && c <= 'z':
+			p.add(patternToken{letters: 1 << (c - 'a')})
+		case c == '?':
+			p.add(patternToken{letters: allLetters})
+		case c == '*':
+			p.add(patternToken{letters: allLetters, repeat: true})
+		case c == '#':
+			p.add(patternToken{letters: consonants})
+		case c == '@':
+			p.add(patternToken{letters: vowels})
+		case c == '[':
+			end := strings.IndexByte(input[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated character class at position %d in pattern %q", i, pattern)
+			}
+
+			letters, err := parseCharacterClass(input[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid character class at position %d in pattern %q: %w", i, pattern, err)
+			}
+
+			p.add(patternToken{letters: letters})
+			i += end
+		default:
+			return nil, fmt.Errorf("invalid character %q at position %d in pattern %q", c, i, pattern)
+		}
+	}
+
+	return p, nil
+}
+
+// parseCharacterClass parses the contents of a '[...]' character class into a bitmask of letters.
+func parseCharacterClass(class string) (uint32, error) {
+	negate := strings.HasPrefix(class, "^")
+	if negate {
+		class = class[1:]
+	}
+
+	var letters uint32
+	for i := 0; i < len(class); i++ {
+		c := class[i]
+		if c < 'a' || c > 'z' {
+			return 0, fmt.Errorf("invalid character %q", c)
+		}
+
+		if i+2 < len(class) && class[i+1] == '-' {
+			end := class[i+2]
+			if end < c || end > 'z' {
+				return 0, fmt.Errorf("invalid range %c-%c", c, end)
+			}
+			for l := c; l <= end; l++ {
+				letters |= 1 << (l - 'a')
+			}
+			i += 2
+		} else {
+			letters |= 1 << (c - 'a')
+		}
+	}
+
+	if negate {
+		letters = allLetters &^ letters
+	}
+
+	if letters == 0 {
+		return 0, fmt.Errorf("class matches no letters")
+	}
+	return letters, nil
+}
+
+// add appends a token to the pattern, collapsing consecutive runs of '*' as they're equivalent to a single one.
+func (p *Pattern) add(token patternToken) {
+	if token.repeat && len(p.tokens) > 0 && p.tokens[len(p.tokens)-1].repeat {
+		return
+	}
+
+	p.tokens = append(p.tokens, token)
+	if !token.repeat {
+		p.fixed++
+	}
+}
+
+// String returns the source text of the pattern.
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// MinLength returns the minimum number of letters a word must have to match the pattern.
+func (p *Pattern) MinLength() int {
+	return p.fixed
+}
+
+// variable determines whether the pattern can match words of different lengths.
+func (p *Pattern) variable() bool {
+	return p.fixed != len(p.tokens)
+}
+
+// Matches determines whether the entire word matches the pattern.
+func (p *Pattern) Matches(word string) bool {
+	state := p.start()
+	for i := 0; i < len(word) && !state.empty(); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return false
+		}
+		state = p.step(state, word[i])
+	}
+	return p.complete(state)
+}
+
+// literalPrefix returns the letters that every match of the pattern must start with.
+func (p *Pattern) literalPrefix() string {
+	var prefix []byte
+	for _, token := range p.tokens {
+		if token.repeat || bits.OnesCount32(token.letters) != 1 {
+			break
+		}
+		prefix = append(prefix, 'a'+byte(bits.TrailingZeros32(token.letters)))
+	}
+	return string(prefix)
+}
+
+// start returns the state of the pattern before any letters have been consumed.
+func (p *Pattern) start() patternState {
+	state := make(patternState, (len(p.tokens)+64)/64)
+	state.set(0)
+	return p.close(state)
+}
+
+// step returns the state of the pattern after consuming the given letter in the given state.
+func (p *Pattern) step(state patternState, letter byte) patternState {
+	next := make(patternState, len(state))
+	bit := uint32(1) << (letter - 'a')
+	for i, token := range p.tokens {
+		if state.has(i) && token.letters&bit != 0 {
+			if token.repeat {
+				next.set(i)
+			} else {
+				next.set(i + 1)
+			}
+		}
+	}
+	return p.close(next)
+}
+
+// close adds positions reachable by skipping over '*' tokens, which may match no letters.
+func (p *Pattern) close(state patternState) patternState {
+	for i, token := range p.tokens {
+		if token.repeat && state.has(i) {
+			state.set(i + 1)
+		}
+	}
+	return state
+}
+
+// letters returns a bitmask of the letters that could be consumed next in the given state.
+func (p *Pattern) letters(state patternState) uint32 {
+	var letters uint32
+	for i, token := range p.tokens {
+		if state.has(i) {
+			letters |= token.letters
+		}
+	}
+	return letters
+}
+
+// complete determines whether the pattern could end in the given state.
+func (p *Pattern) complete(state patternState) bool {
+	return state.has(len(p.tokens))
+}
+
+// patternAutomaton runs a pattern as a vellum.Automaton, so that it can be matched directly against a WordList's FST
+// and whole branches of words that can't match are skipped. States are built lazily as the FST is traversed; state 0
+// matches nothing.
+type patternAutomaton struct {
+	pattern     *Pattern
+	states      []patternState
+	ids         map[string]int
+	transitions [][26]int
+}
+
+func newPatternAutomaton(pattern *Pattern) *patternAutomaton {
+	a := &patternAutomaton{pattern: pattern, ids: make(map[string]int)}
+	start := pattern.start()
+	a.state(make(patternState, len(start)))
+	a.state(start)
+	return a
+}
+
+// state returns the number of the given state, allocating a new one if it hasn't been seen before.
+func (a *patternAutomaton) state(s patternState) int {
+	key := s.key()
+	if id, ok := a.ids[key]; ok {
+		return id
+	}
+
+	id := len(a.states)
+	a.states = append(a.states, s)
+	a.ids[key] = id
+	a.transitions = append(a.transitions, [26]int{})
+	for i := range a.transitions[id] {
+		a.transitions[id][i] = -1
+	}
+	return id
+}
+
+func (a *patternAutomaton) Start() int {
+	return 1
+}
+
+func (a *patternAutomaton) IsMatch(i int) bool {
+	return a.pattern.complete(a.states[i])
+}
+
+func (a *patternAutomaton) CanMatch(i int) bool {
+	return !a.states[i].empty()
+}
+
+func (a *patternAutomaton) WillAlwaysMatch(int) bool {
+	return false
+}
+
+func (a *patternAutomaton) Accept(i int, b byte) int {
+	if b < 'a' || b > 'z' {
+		return 0
+	}
+
+	if next := a.transitions[i][b-'a']; next != -1 {
+		return next
+	}
+
+	next := a.state(a.pattern.step(a.states[i], b))
+	a.transitions[i][b-'a'] = next
+	return next
+}
+
+// key returns a string uniquely identifying the state, for use as a map key.
+func (s patternState) key() string {
+	var b []byte
+	for i := range s {
+		b = binary.LittleEndian.AppendUint64(b, s[i])
+	}
+	return string(b)
+}
+
+func (s patternState) set(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s patternState) has(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+func (s patternState) empty() bool {
+	for i := range s {
+		if s[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
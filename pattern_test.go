@@ -0,0 +1,85 @@
+package kowalski
+
+import (
+	"testing"
+)
+
+func TestPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		word    string
+		want    bool
+	}{
+		{"cat", "cat", true},
+		{"cat", "cats", false},
+		{"c?t", "cot", true},
+		{"c?t", "ct", false},
+		{"c*t", "ct", true},
+		{"c*t", "cabinet", true},
+		{"c*t", "cabinets", false},
+		{"*s", "cats", true},
+		{"**s", "cats", true},
+		{"#@#", "cat", true},
+		{"#@#", "act", false},
+		{"c[aeiou]t", "cut", true},
+		{"c[aeiou]t", "cyt", false},
+		{"c[^aeiou]t", "cyt", true},
+		{"c[^aeiou]t", "cat", false},
+		{"[a-c]at", "bat", true},
+		{"[a-c]at", "rat", false},
+		{"CAT", "cat", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.word, func(t *testing.T) {
+			p, err := CompilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("CompilePattern() error = %v", err)
+			}
+			if got := p.Matches(tt.word); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	tests := []string{
+		"c[at",
+		"c[]t",
+		"c[^a-z]t",
+		"c[z-a]t",
+		"c.t",
+		"c[a1]t",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			if _, err := CompilePattern(tt); err == nil {
+				t.Errorf("CompilePattern() expected error")
+			}
+		})
+	}
+}
+
+func TestPatternLiteralPrefix(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"cat", "cat"},
+		{"ca?", "ca"},
+		{"c[a]t*", "cat"},
+		{"c*t", "c"},
+		{"?at", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, _ := CompilePattern(tt.pattern)
+			if got := p.literalPrefix(); got != tt.want {
+				t.Errorf("literalPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package kowalski
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// RankedResult is a result from one of the solvers, along with a score indicating how common it is.
+type RankedResult struct {
+	Result string  `json:"result"`
+	Score  float64 `json:"score"`
+}
+
+// Rank scores each of the results according to how common its words are in the dictionary, and returns them ordered
+// from most to least common. Results containing multiple words are scored using the geometric mean of their words'
+// frequencies, so that phrases with different numbers of words can be compared fairly; any word with no frequency
+// gives the entire phrase a score of zero.
+//
+// If the dictionary does not implement Weighted, all results are given a score of zero and their order is preserved.
+func Rank(dictionary Dictionary, results []string) []RankedResult {
+	ranked := make([]RankedResult, len(results))
+	weighted, ok := dictionary.(Weighted)
+	for i := range results {
+		ranked[i].Result = results[i]
+		if ok {
+			ranked[i].Score = phraseScore(weighted, results[i])
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// phraseScore returns the geometric mean of the frequencies of each space- or hyphen-separated word in the phrase.
+func phraseScore(dictionary Weighted, phrase string) float64 {
+	words := strings.FieldsFunc(phrase, func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for i := range words {
+		frequency := dictionary.Frequency(words[i])
+		if frequency == 0 {
+			return 0
+		}
+		total += math.Log(float64(frequency))
+	}
+	return math.Exp(total / float64(len(words)))
+}
+
+// rankResults returns the results reordered from most to least common, discarding the scores.
+func rankResults(dictionary Dictionary, results []string) []string {
+	if _, ok := dictionary.(Weighted); !ok {
+		return results
+	}
+
+	ranked := Rank(dictionary, results)
+	res := make([]string, len(ranked))
+	for i := range ranked {
+		res[i] = ranked[i].Result
+	}
+	return res
+}
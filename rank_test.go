@@ -0,0 +1,53 @@
+package kowalski
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("foo\t10\nbar\t1000\nbaz\t100\nquux\n"))
+
+	tests := []struct {
+		name    string
+		results []string
+		want    []RankedResult
+	}{
+		{
+			"single words",
+			[]string{"bar", "baz", "foo"},
+			[]RankedResult{{"bar", 1000}, {"baz", 100}, {"foo", 10}},
+		},
+		{
+			"phrases",
+			[]string{"foo foo", "bar foo", "bar bar"},
+			[]RankedResult{{"bar bar", 1000}, {"bar foo", 100}, {"foo foo", 10}},
+		},
+		{
+			"unweighted words",
+			[]string{"quux", "foo", "bar quux"},
+			[]RankedResult{{"foo", 10}, {"quux", 0}, {"bar quux", 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Rank(list, tt.results)
+			for i := range got {
+				// Round to avoid floating point noise from the geometric mean
+				got[i].Score = float64(int(got[i].Score + 0.5))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankUnweighted(t *testing.T) {
+	want := []RankedResult{{"foo", 0}, {"bar", 0}}
+	if got := Rank(testChecker, []string{"foo", "bar"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %v, want %v", got, want)
+	}
+}
@@ -2,11 +2,13 @@ package kowalski
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/gob"
 	"fmt"
 	"github.com/bits-and-blooms/bloom/v3"
 	"io"
 	"strings"
+	"time"
 )
 
 // SpellChecker provides a way to tell whether a word exists in a dictionary.
@@ -14,13 +16,45 @@ type SpellChecker struct {
 	primary     *bloom.BloomFilter
 	secondaries [2]*bloom.BloomFilter
 	roots       *bloom.BloomFilter
+	info        ModelInfo
 }
 
-// LoadSpellChecker attempts to load a SpellChecker that was previously saved with SaveSpellChecker.
+// LoadSpellChecker attempts to load a SpellChecker that was previously saved with SaveSpellChecker. Models saved by
+// older versions without any metadata are also supported; their Info will have a Version of 0.
 func LoadSpellChecker(reader io.Reader) (*SpellChecker, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, modelMagic) {
+		if checker, err := decodeLegacySpellChecker(data); err == nil {
+			return checker, nil
+		}
+		return nil, ErrUnknownModelFormat
+	}
+
+	info, body, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Kind != SpellCheckerModel {
+		return nil, fmt.Errorf("model contains a %s, not a spell checker", info.Kind)
+	}
+
+	return decodeSpellChecker(body, info)
+}
+
+// decodeLegacySpellChecker decodes a spell checker saved before model files contained metadata.
+func decodeLegacySpellChecker(data []byte) (*SpellChecker, error) {
+	return decodeSpellChecker(data, ModelInfo{Kind: SpellCheckerModel})
+}
+
+func decodeSpellChecker(body []byte, info ModelInfo) (*SpellChecker, error) {
 	var filters []*bloom.BloomFilter
 
-	if err := gob.NewDecoder(reader).Decode(&filters); err != nil {
+	if err := gob.NewDecoder(bytes.NewReader(body)).Decode(&filters); err != nil {
 		return nil, err
 	}
 
@@ -35,6 +69,7 @@ func LoadSpellChecker(reader io.Reader) (*SpellChecker, error) {
 			filters[2],
 		},
 		roots: filters[3],
+		info:  info,
 	}, nil
 }
 
@@ -48,35 +83,83 @@ func SaveSpellChecker(writer io.Writer, checker *SpellChecker) error {
 		checker.roots,
 	}
 
-	return gob.NewEncoder(writer).Encode(filters)
+	body := &bytes.Buffer{}
+	if err := gob.NewEncoder(body).Encode(filters); err != nil {
+		return err
+	}
+
+	return encodeModel(writer, checker.info, body.Bytes())
 }
 
 // CreateSpellChecker creates a new SpellChecker by reading words line-by-line from the given reader.
-// The wordCount parameter should be an approximation of the number of words available.
+// The wordCount parameter should be an approximation of the number of words available; it is ignored if the
+// CountUniqueWords option is given.
 //
 // This is likely to be a relatively expensive operation; for routine use prefer saving the spell
 // checker via SaveSpellChecker and restoring it with LoadSpellChecker.
-func CreateSpellChecker(reader io.Reader, wordCount int) (*SpellChecker, error) {
+func CreateSpellChecker(reader io.Reader, wordCount int, opts ...ModelOption) (*SpellChecker, error) {
+	o, err := applyModelOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixCount := int(float64(wordCount) * o.prefixesPerWord)
+	if o.countUnique {
+		words, _, err := readWords(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		wordCount = len(words)
+		prefixCount = countPrefixes(words)
+		reader = strings.NewReader(strings.Join(words, "\n"))
+	}
+
 	c := &SpellChecker{
-		primary: bloom.NewWithEstimates(uint(wordCount), 0.001),
+		primary: newFilter(wordCount, o.falsePositiveRate),
 		secondaries: [2]*bloom.BloomFilter{
-			bloom.NewWithEstimates(uint(wordCount/2), 0.001),
-			bloom.NewWithEstimates(uint(wordCount/2), 0.001),
+			newFilter(wordCount/2, o.falsePositiveRate),
+			newFilter(wordCount/2, o.falsePositiveRate),
+		},
+		roots: newFilter(prefixCount, o.prefixFalsePositiveRate),
+		info: ModelInfo{
+			Version:                 modelFormatVersion,
+			Kind:                    SpellCheckerModel,
+			Source:                  o.source,
+			FalsePositiveRate:       o.falsePositiveRate,
+			PrefixFalsePositiveRate: o.prefixFalsePositiveRate,
+			Created:                 time.Now().UTC().Truncate(time.Second),
 		},
-		roots: bloom.NewWithEstimates(uint(wordCount*10), 0.1),
 	}
 
 	scanner := bufio.NewScanner(reader)
+	seen := make(map[string]bool)
 	counter := 0
 	for scanner.Scan() {
-		line := strings.ToLower(scanner.Text())
-		c.addWord(line, counter)
+		word, _ := ParseWord(scanner.Text())
+		if word == "" || seen[word] {
+			continue
+		}
+
+		seen[word] = true
+		c.addWord(word, counter)
 		counter = 1 - counter
+		c.info.WordCount++
 	}
 
 	return c, scanner.Err()
 }
 
+// newFilter creates a bloom filter sized for the given number of items and false positive rate.
+func newFilter(items int, rate float64) *bloom.BloomFilter {
+	return bloom.NewWithEstimates(uint(max(items, 1)), rate)
+}
+
+// Info returns metadata describing how the SpellChecker was built.
+func (c *SpellChecker) Info() ModelInfo {
+	return c.info
+}
+
 // addWord adds a new word to the spell checker - that is, it adds it to the primary bloom filter and one of the
 // two secondaries, and also adds all prefixes of the word to the roots filter.
 func (c *SpellChecker) addWord(word string, secondary int) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -56,3 +57,55 @@ func TestSaveLoadSpellChecker(t *testing.T) {
 		t.Errorf("Saved spell checker differs when loaded: got %v, wanted %v", saved, testChecker)
 	}
 }
+
+func TestLoadSpellCheckerRejectsWordList(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := SaveWordList(buffer, testWordList); err != nil {
+		t.Fatalf("SaveWordList() failed: %v", err)
+	}
+
+	if _, err := LoadSpellChecker(buffer); err == nil {
+		t.Errorf("LoadSpellChecker() loaded a word list without error")
+	}
+}
+
+func TestCreateSpellCheckerOptions(t *testing.T) {
+	input := "foo\nbar\nfoo\nbaz\n"
+
+	checker, err := CreateSpellChecker(strings.NewReader(input), 100, CountUniqueWords, WithFalsePositiveRate(0.0001))
+	if err != nil {
+		t.Fatalf("CreateSpellChecker() error = %v", err)
+	}
+
+	if info := checker.Info(); info.WordCount != 3 || info.FalsePositiveRate != 0.0001 {
+		t.Errorf("CreateSpellChecker() info = %+v, want 3 words at 0.0001", info)
+	}
+
+	for _, word := range []string{"foo", "bar", "baz"} {
+		if !checker.Valid(word) {
+			t.Errorf("Valid(%s) = false, want true", word)
+		}
+	}
+
+	if _, err := CreateSpellChecker(strings.NewReader(input), 100, WithFalsePositiveRate(1.5)); err == nil {
+		t.Errorf("CreateSpellChecker() accepted an invalid false positive rate")
+	}
+}
+
+func TestCreateSpellCheckerWordCount(t *testing.T) {
+	checker, err := CreateSpellChecker(strings.NewReader("foo\t10\n\nbar\nFOO\t3\nbaz\n\n"), 100)
+	if err != nil {
+		t.Fatalf("CreateSpellChecker() error = %v", err)
+	}
+
+	if got := checker.Info().WordCount; got != 3 {
+		t.Errorf("CreateSpellChecker() word count = %d, want 3", got)
+	}
+}
+
+func TestCountPrefixes(t *testing.T) {
+	// b, ba, bar, baz, f, fo, foo
+	if got := countPrefixes([]string{"bar", "baz", "foo"}); got != 7 {
+		t.Errorf("countPrefixes() = %d, want 7", got)
+	}
+}
@@ -1,36 +1,318 @@
 package kowalski
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
 
-var t9mapping = map[uint8][]rune{
-	'2': []rune("abc"),
-	'3': []rune("def"),
-	'4': []rune("ghi"),
-	'5': []rune("jkl"),
-	'6': []rune("mno"),
-	'7': []rune("pqrs"),
-	'8': []rune("tuv"),
-	'9': []rune("wxyz"),
+// T9Layout maps each key on a phone keypad to the characters it produces, in the order they're reached by pressing
+// the key repeatedly. A key that produces a space separates words.
+type T9Layout map[rune]string
+
+var (
+	// ITULayout is the standard ITU E.161 keypad, with letters on keys 2 to 9 and a space on 0.
+	ITULayout = T9Layout{
+		'0': " ",
+		'2': "abc",
+		'3': "def",
+		'4': "ghi",
+		'5': "jkl",
+		'6': "mno",
+		'7': "pqrs",
+		'8': "tuv",
+		'9': "wxyz",
+	}
+
+	// NokiaLayout is the keypad used by older Nokia phones, which adds punctuation on key 1 and cycles through the
+	// digit itself after each key's letters.
+	NokiaLayout = T9Layout{
+		'0': " 0",
+		'1': ".,'?!\"1-()@/:_",
+		'2': "abc2",
+		'3': "def3",
+		'4': "ghi4",
+		'5': "jkl5",
+		'6': "mno6",
+		'7': "pqrs7",
+		'8': "tuv8",
+		'9': "wxyz9",
+	}
+)
+
+// T9Layouts contains the predefined keypad layouts, by name.
+var T9Layouts = map[string]T9Layout{
+	"itu":   ITULayout,
+	"nokia": NokiaLayout,
 }
 
-// FromT9 takes an input that represents a sequence of key presses on a T9 keyboard and returns possible
-// words that match. The input should not contain spaces (the "0" digit) - words should be solved independently,
-// to avoid an explosion of possible results.
-func FromT9(checker *SpellChecker, input string) []string {
-	return fromT9(checker, input, "")
+// ParseT9Layout parses the name of a layout in T9Layouts, or a custom layout given as comma-separated keys and their
+// characters, such as "2:abc,3:def,0: ". Characters are case-insensitive.
+func ParseT9Layout(spec string) (T9Layout, error) {
+	if layout, ok := T9Layouts[strings.ToLower(spec)]; ok {
+		return layout, nil
+	}
+
+	if !strings.Contains(spec, ":") {
+		return nil, fmt.Errorf("unknown keypad layout: %s", spec)
+	}
+
+	layout := make(T9Layout)
+	for _, entry := range strings.Split(spec, ",") {
+		key, chars, ok := strings.Cut(entry, ":")
+		keys := []rune(key)
+		if !ok || len(keys) != 1 || chars == "" {
+			return nil, fmt.Errorf("invalid keypad key: %q", entry)
+		}
+		if _, ok := layout[keys[0]]; ok {
+			return nil, fmt.Errorf("keypad key %c given more than once", keys[0])
+		}
+		layout[keys[0]] = strings.ToLower(chars)
+	}
+	return layout, nil
+}
+
+// find returns the key that produces the given character and the number of presses needed, checking keys in order
+// so that the result is deterministic.
+func (l T9Layout) find(char rune) (rune, int, bool) {
+	for _, key := range slices.Sorted(maps.Keys(l)) {
+		if i := strings.IndexRune(l[key], char); i != -1 {
+			return key, len([]rune(l[key][:i])) + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// T9Options configures how key presses are interpreted.
+type T9Options struct {
+	// Layout is the keypad being used. If nil, ITULayout is used.
+	Layout T9Layout
+	// MultiTap indicates each character was entered by pressing its key repeatedly (so "44 444" is "hi"), instead of
+	// once per character with the phone predicting the word. Whitespace then marks a pause between characters on the
+	// same key, rather than a boundary between words.
+	MultiTap bool
+}
+
+func (o T9Options) layout() T9Layout {
+	if o.Layout == nil {
+		return ITULayout
+	}
+	return o.Layout
 }
 
-func fromT9(checker *SpellChecker, input, prefix string) []string {
-	var res []string
-	if opts, ok := t9mapping[input[0]]; ok {
-		for i := range opts {
-			next := fmt.Sprintf("%s%c", prefix, opts[i])
-			if len(input) > 1 && checker.Prefix(next) {
-				res = append(res, fromT9(checker, input[1:], next)...)
-			} else if len(input) == 1 && checker.Valid(next) {
-				res = append(res, next)
+// ParseT9Options splits options in the form "key=value" from the rest of the input, returning the remaining fields
+// joined with spaces along with the parsed options. The supported keys are:
+//
+//   - layout: the name of a layout in T9Layouts, or a custom layout (see ParseT9Layout)
+//   - multitap: "true" or "false" to override whether the input is treated as multi-tap
+//
+// If multitap isn't given, the input is treated as multi-tap if every whitespace-separated part of it is a single
+// key pressed repeatedly, and at least one part contains more than one press (such as "44 444"). The returned
+// options always have a layout set.
+func ParseT9Options(input string) (string, T9Options, error) {
+	var (
+		options  = T9Options{Layout: ITULayout}
+		rest     []string
+		multiTap *bool
+	)
+
+	for _, field := range strings.Fields(input) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			rest = append(rest, field)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "layout":
+			layout, err := ParseT9Layout(value)
+			if err != nil {
+				return "", T9Options{}, err
 			}
+			options.Layout = layout
+		case "multitap":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return "", T9Options{}, fmt.Errorf("option %s requires true or false, got %q", key, value)
+			}
+			multiTap = &b
+		default:
+			return "", T9Options{}, fmt.Errorf("unknown option: %s", key)
+		}
+	}
+
+	if multiTap != nil {
+		options.MultiTap = *multiTap
+	} else {
+		options.MultiTap = looksMultiTap(rest, options.Layout)
+	}
+
+	return strings.Join(rest, " "), options, nil
+}
+
+// looksMultiTap determines whether each of the fields is a single key on the layout pressed repeatedly, with at least
+// one of them pressed more than once.
+func looksMultiTap(fields []string, layout T9Layout) bool {
+	repeated := false
+	for _, field := range fields {
+		keys := []rune(field)
+		if _, ok := layout[keys[0]]; !ok || strings.Trim(field, string(keys[0])) != "" {
+			return false
 		}
+		repeated = repeated || len(keys) > 1
 	}
-	return res
+	return repeated
+}
+
+// FromT9 takes an input that represents a sequence of key presses on a phone keypad and returns possible words or
+// phrases that match. Words are separated by whitespace or by a key that produces a space (the "0" key on the ITU
+// layout); each word is decoded independently and the results joined with spaces.
+func FromT9(checker Dictionary, input string, options T9Options) []string {
+	return slices.Collect(FromT9Seq(context.Background(), checker, input, options))
+}
+
+// FromT9Seq returns an iterator over the words that FromT9 would return. Words are yielded as they are found;
+// iteration stops early if the context is cancelled, in which case callers should check ctx.Err().
+func FromT9Seq(ctx context.Context, checker Dictionary, input string, options T9Options) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if keys, ok := parseT9(input, options.layout()); ok {
+			fromT9(ctx, checker, keys, options, yield)
+		}
+	}
+}
+
+// ToT9 encodes the input as key presses on a phone keypad. Without multi-tap, each character is a single press of
+// its key. With multi-tap, each character is represented by pressing its key repeatedly, and characters are
+// separated by spaces. Letters are case-insensitive. An error is returned if a character isn't on the keypad.
+func ToT9(input string, options T9Options) (string, error) {
+	layout := options.layout()
+
+	var presses []string
+	for _, char := range strings.ToLower(strings.Join(strings.Fields(input), " ")) {
+		key, count, ok := layout.find(char)
+		if !ok {
+			if char == ' ' && !options.MultiTap {
+				presses = append(presses, " ")
+				continue
+			}
+			return "", fmt.Errorf("character %q is not on the keypad", char)
+		}
+
+		if options.MultiTap {
+			presses = append(presses, strings.Repeat(string(key), count))
+		} else {
+			presses = append(presses, string(key))
+		}
+	}
+
+	if options.MultiTap {
+		return strings.Join(presses, " "), nil
+	}
+	return strings.Join(presses, ""), nil
+}
+
+// parseT9 normalises key presses, replacing runs of whitespace with a single space. Returns false if the input
+// contains anything other than whitespace and keys on the layout.
+func parseT9(input string, layout T9Layout) (string, bool) {
+	b := &strings.Builder{}
+	for _, r := range strings.Join(strings.Fields(input), " ") {
+		if _, ok := layout[r]; !ok && r != ' ' {
+			return "", false
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), b.Len() > 0
+}
+
+// fromT9 performs a depth-first search for ways to decode the key presses into dictionary words, passing each
+// complete result to yield. Returns false if the search should stop.
+func fromT9(ctx context.Context, checker Dictionary, keys string, options T9Options, yield func(string) bool) bool {
+	var (
+		layout  = options.layout()
+		presses = []rune(keys)
+		words   []string
+		search  func(i int, current string) bool
+	)
+
+	// endWord completes the current word (if there is one) and continues the search from i.
+	endWord := func(i int, current string) bool {
+		if current == "" {
+			return search(i, "")
+		}
+		if !checker.Valid(current) {
+			return true
+		}
+
+		words = append(words, current)
+		ok := search(i, "")
+		words = words[:len(words)-1]
+		return ok
+	}
+
+	// next continues the search from i with the given character appended, or as a word boundary if it's a space.
+	next := func(i int, current string, char rune) bool {
+		if char == ' ' {
+			return endWord(i, current)
+		}
+
+		word := current + string(char)
+		if !checker.Prefix(word) {
+			return true
+		}
+		return search(i, word)
+	}
+
+	search = func(i int, current string) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if i == len(presses) {
+			if current != "" {
+				if !checker.Valid(current) {
+					return true
+				}
+				return yield(strings.Join(append(words, current), " "))
+			}
+			if len(words) > 0 {
+				return yield(strings.Join(words, " "))
+			}
+			return true
+		}
+
+		if presses[i] == ' ' {
+			if options.MultiTap {
+				return search(i+1, current)
+			}
+			return endWord(i+1, current)
+		}
+
+		chars := []rune(layout[presses[i]])
+		if !options.MultiTap {
+			for _, char := range chars {
+				if !next(i+1, current, char) {
+					return false
+				}
+			}
+			return true
+		}
+
+		run := 1
+		for i+run < len(presses) && presses[i+run] == presses[i] {
+			run++
+		}
+
+		for count := 1; count <= min(run, len(chars)); count++ {
+			if !next(i+count, current, chars[count-1]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return search(0, "")
 }
@@ -1,7 +1,10 @@
 package kowalski
 
 import (
+	"context"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -19,9 +22,117 @@ func TestFromT9(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := FromT9(testChecker, tt.query); !reflect.DeepEqual(got, tt.want) {
+			if got := FromT9(testChecker, tt.query, T9Options{}); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("FromT9() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestFromT9Options(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("hi\ngi\nih\nhello\nworld\nit's\n"))
+
+	tests := []struct {
+		name    string
+		query   string
+		options T9Options
+		want    []string
+	}{
+		{"single word", "44", T9Options{}, []string{"gi", "hi", "ih"}},
+		{"zero separates words", "43556096753", T9Options{}, []string{"hello world"}},
+		{"space separates words", "43556 96753", T9Options{}, []string{"hello world"}},
+		{"one is invalid on itu", "4811487", T9Options{}, nil},
+		{"nokia punctuation", "4817", T9Options{Layout: NokiaLayout}, []string{"it's"}},
+		{"multi-tap", "44 444", T9Options{MultiTap: true}, []string{"hi"}},
+		{"multi-tap without pauses", "44444", T9Options{MultiTap: true}, []string{"hi", "ih"}},
+		{"multi-tap words", "44 33 555 555 666 0 9 666 777 555 3", T9Options{MultiTap: true}, []string{"hello world"}},
+		{"custom layout", "12", T9Options{Layout: T9Layout{'1': "h", '2': "i"}}, []string{"hi"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromT9(list, tt.query, tt.options)
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromT9() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplexFromT9(t *testing.T) {
+	got, err := MultiplexFromT9(context.Background(), []Dictionary{testChecker}, "366", T9Options{})
+	if err != nil {
+		t.Fatalf("MultiplexFromT9() error = %v", err)
+	}
+	if want := [][]string{{"foo"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiplexFromT9() = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MultiplexFromT9(ctx, []Dictionary{testChecker}, "366", T9Options{}); err == nil {
+		t.Errorf("MultiplexFromT9() returned no error for a cancelled context")
+	}
+}
+
+func TestToT9(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options T9Options
+		want    string
+		wantErr bool
+	}{
+		{"word", "Hello", T9Options{}, "43556", false},
+		{"words", "hello world", T9Options{}, "43556096753", false},
+		{"multi-tap", "hello world", T9Options{MultiTap: true}, "44 33 555 555 666 0 9 666 777 555 3", false},
+		{"nokia", "it's 2", T9Options{Layout: NokiaLayout, MultiTap: true}, "444 8 111 7777 0 2222", false},
+		{"unsupported character", "it's", T9Options{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToT9(tt.input, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToT9() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ToT9() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseT9Options(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         string
+		wantLayout   T9Layout
+		wantMultiTap bool
+		wantErr      bool
+	}{
+		{"defaults", "43556", "43556", ITULayout, false, false},
+		{"detects multi-tap", "44 444", "44 444", ITULayout, true, false},
+		{"single presses aren't multi-tap", "4 3", "4 3", ITULayout, false, false},
+		{"multi-tap override", "44 444 multitap=false", "44 444", ITULayout, false, false},
+		{"named layout", "layout=nokia 4811", "4811", NokiaLayout, false, false},
+		{"custom layout", "layout=1:ab,2:cd 12", "12", T9Layout{'1': "ab", '2': "cd"}, false, false},
+		{"unknown layout", "layout=dvorak 12", "", nil, false, true},
+		{"invalid custom layout", "layout=12:ab 12", "", nil, false, true},
+		{"unknown option", "foo=bar 12", "", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, options, err := ParseT9Options(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseT9Options() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || !reflect.DeepEqual(options.Layout, tt.wantLayout) || options.MultiTap != tt.wantMultiTap {
+				t.Errorf("ParseT9Options() = %q, %v, want %q, %v", got, options, tt.want, T9Options{Layout: tt.wantLayout, MultiTap: tt.wantMultiTap})
+			}
+		})
+	}
+}
@@ -100,7 +100,7 @@ type WordCheckResult struct {
 
 // CheckWords splits the input into words and checks each one against the spell checker.
 // Returns a slice of results for each line.
-func CheckWords(checker *SpellChecker, input string) [][]WordCheckResult {
+func CheckWords(checker Dictionary, input string) [][]WordCheckResult {
 	lines := strings.Split(input, "\n")
 	results := make([][]WordCheckResult, len(lines))
 
@@ -1,5 +1,12 @@
 package kowalski
 
+import (
+	"context"
+	"iter"
+	"slices"
+	"sort"
+)
+
 func reverse(input []byte, start int) []byte {
 	for left, right := start, len(input)-1; left < right; left, right = left+1, right-1 {
 		input[left], input[right] = input[right], input[left]
@@ -18,3 +25,33 @@ func max(a, b int) int {
 		return b
 	}
 }
+
+// collect gathers all results from the sequence into a sorted slice, returning the context's error instead if it was
+// cancelled before the sequence finished.
+func collect(ctx context.Context, seq iter.Seq[string]) ([]string, error) {
+	return collectN(ctx, seq, 0)
+}
+
+// collectN gathers up to limit results from the sequence into a sorted slice, stopping the sequence once the limit is
+// reached. If limit is zero or negative, all results are collected. The context's error is returned instead if it
+// was cancelled before the sequence finished.
+func collectN(ctx context.Context, seq iter.Seq[string], limit int) ([]string, error) {
+	var res []string
+	if limit > 0 {
+		for item := range seq {
+			res = append(res, item)
+			if len(res) >= limit {
+				break
+			}
+		}
+	} else {
+		res = slices.Collect(seq)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(res)
+	return res, nil
+}
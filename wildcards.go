@@ -3,42 +3,118 @@ package kowalski
 import (
 	"context"
 	"fmt"
-	"sort"
-	"strings"
+	"iter"
 )
 
-// Match returns all valid words that match the given pattern, expanding '?' as a single character wildcard
-func Match(ctx context.Context, checker *SpellChecker, pattern string) ([]string, error) {
-	res, _, err := findMatch(ctx, checker, strings.ToLower(pattern), false, 0)
-	return res, err
+// defaultMaxVariableWords is the maximum number of words MultiMatch will return for patterns containing '*' if the
+// caller doesn't specify one, as otherwise the number of possible phrases is practically unbounded.
+const defaultMaxVariableWords = 3
+
+// Match returns all valid words that match the given pattern. See Pattern for the supported syntax; for example '?'
+// expands to any single letter, and '*' to any run of letters. An error is returned if the pattern is invalid.
+func Match(ctx context.Context, checker Dictionary, pattern string) ([]string, error) {
+	if _, err := CompilePattern(pattern); err != nil {
+		return nil, err
+	}
+	return collect(ctx, MatchSeq(ctx, checker, pattern))
 }
 
-// MultiMatch returns valid sequences of words that match the given pattern, expanding '?' as a single character
-// wildcard. To reduce the search space, multi-match will first try to look for matches consisting only of longer
-// words, then gradually reduce that threshold until at least one match is found.
-func MultiMatch(ctx context.Context, checker *SpellChecker, pattern string) ([]string, error) {
-	i := len(pattern) / 2
-	if i > 5 {
-		i = 5
+// MatchSeq returns an iterator over all valid words that match the given pattern. See Pattern for the supported
+// syntax; invalid patterns yield no results. Words are yielded as they are found; iteration stops early if the
+// context is cancelled, in which case callers should check ctx.Err().
+//
+// If the checker is a WordList, the pattern is run as an automaton over its FST, so only words that could match are
+// visited wherever the pattern's wildcards are.
+func MatchSeq(ctx context.Context, checker Dictionary, pattern string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		p, err := CompilePattern(pattern)
+		if err != nil {
+			return
+		}
+
+		if list, ok := checker.(*WordList); ok {
+			for word := range list.search(newPatternAutomaton(p)) {
+				if ctx.Err() != nil || !yield(word) {
+					return
+				}
+			}
+		} else if enumerator, ok := checker.(Enumerator); ok {
+			enumerateMatches(ctx, enumerator, p, yield)
+		} else {
+			findMatch(ctx, checker, p, false, MultiWordOptions{}, yield)
+		}
 	}
+}
 
-	for i > 0 {
-		res, _, err := findMatch(ctx, checker, strings.ToLower(pattern), true, i)
+// MultiMatch returns valid sequences of words that match the given pattern. See Pattern for the supported syntax;
+// an error is returned if the pattern is invalid. The results can be constrained using options; if no minimum word
+// length is given, multi-match will first try to look for matches consisting only of longer words, then gradually
+// reduce that threshold until at least one match is found. If the pattern contains '*' and no maximum number of
+// words is given, results are limited to three words. If an enumeration is given, word boundaries are fixed by it
+// instead; an empty pattern then matches any letters, and an error is returned if the pattern can't fit it.
+func MultiMatch(ctx context.Context, checker Dictionary, pattern string, options MultiWordOptions) ([]string, error) {
+	if _, err := compileMultiPattern(pattern, options); err != nil {
+		return nil, err
+	}
+	return collect(ctx, MultiMatchSeq(ctx, checker, pattern, options))
+}
+
+// MultiMatchSeq returns an iterator over the results of MultiMatch; invalid patterns, and patterns that can't fit the
+// enumeration, yield no results. Results are yielded as they are found; iteration stops early if the context is
+// cancelled, in which case callers should check ctx.Err().
+func MultiMatchSeq(ctx context.Context, checker Dictionary, pattern string, options MultiWordOptions) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		p, err := compileMultiPattern(pattern, options)
 		if err != nil {
-			return nil, err
+			return
+		}
+
+		if options.MaxWords == 0 && p.variable() {
+			options.MaxWords = defaultMaxVariableWords
 		}
 
-		if len(res) > 0 {
-			return res, nil
+		if options.MinWordLength > 0 || options.enumerated() {
+			findMatch(ctx, checker, p, true, options, yield)
+			return
+		}
+
+		for i := max(min(p.MinLength()/2, 5), 1); i > 0; i-- {
+			found, stopped := false, false
+			options.MinWordLength = i
+			findMatch(ctx, checker, p, true, options, func(match string) bool {
+				found = true
+				stopped = !yield(match)
+				return !stopped
+			})
+
+			if found || stopped || ctx.Err() != nil {
+				return
+			}
 		}
-		i--
 	}
-	return nil, nil
+}
+
+// compileMultiPattern compiles the pattern for MultiMatch, checking that it can fit the enumeration in the options (if
+// any). An empty pattern is replaced with one matching any letters that fit the enumeration.
+func compileMultiPattern(pattern string, options MultiWordOptions) (*Pattern, error) {
+	if pattern == "" {
+		pattern = options.Enumeration.Pattern()
+	}
+
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := options.checkEnumeration(pattern, p.MinLength(), p.variable()); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 // OffByOne returns all words that can be made by performing one character change on the input. The input is
 // assumed to be a single, lowercase word containing a-z chars only.
-func OffByOne(ctx context.Context, checker *SpellChecker, input string) ([]string, error) {
+func OffByOne(ctx context.Context, checker Dictionary, input string) ([]string, error) {
 	words := map[string]bool{}
 	for i := range input {
 		res, err := Match(ctx, checker, fmt.Sprintf("%s?%s", input[0:i], input[i+1:]))
@@ -60,59 +136,73 @@ func OffByOne(ctx context.Context, checker *SpellChecker, input string) ([]strin
 	return res, nil
 }
 
-// findMatch returns all valid words that match the given pattern, expanding '?' as a single character wildcard.
-// It will aggressively skip sequences that don't form valid prefixes; the maximum valid prefix length is returned as
-// the second parameter (for cases where matches are returned, this will equal len(word)).
-func findMatch(ctx context.Context, checker *SpellChecker, word string, multiWord bool, minLength int) ([]string, int, error) {
-	maxLength := 0
-	stems := [][]string{{""}}
-	for offset := 0; offset < len(word) && len(stems) > 0; offset++ {
+// enumerateMatches finds all words from the enumerator that match the given pattern, and passes them to yield.
+// Rather than trying every letter at each wildcard, it lists the words that share the pattern's literal prefix and
+// filters them.
+func enumerateMatches(ctx context.Context, enumerator Enumerator, pattern *Pattern, yield func(string) bool) {
+	for word := range enumerator.Words(pattern.literalPrefix(), 0) {
 		if ctx.Err() != nil {
-			return nil, 0, ctx.Err()
+			return
 		}
 
-		newStems := make([][]string, 0, len(stems))
+		if pattern.Matches(word) && !yield(word) {
+			return
+		}
+	}
+}
 
-		var chars []uint8
-		if word[offset] == '?' {
-			chars = []uint8("abcdefghijklmnopqrstuvwxyz")
-		} else {
-			chars = []uint8{word[offset]}
+// findMatch finds all valid words that match the given pattern, and passes them to yield. It performs a depth-first
+// search that aggressively skips sequences that don't form valid prefixes or break the constraints in options,
+// stopping if yield returns false or the context is cancelled.
+func findMatch(ctx context.Context, checker Dictionary, pattern *Pattern, multiWord bool, options MultiWordOptions, yield func(string) bool) {
+	var (
+		words  []string
+		search func(depth int, state patternState, current string) bool
+	)
+
+	search = func(depth int, state patternState, current string) bool {
+		if ctx.Err() != nil {
+			return false
 		}
 
-		for _, nextChar := range chars {
-			if ctx.Err() != nil {
-				return nil, 0, ctx.Err()
+		if pattern.complete(state) && options.allowsWord(len(words), len(current)) && checker.Valid(current) {
+			result := append(words, current)
+			if options.accepts(result) && !yield(options.join(result)) {
+				return false
 			}
+		}
 
-			for s := range stems {
-				stem := stems[s]
-				if newWord := fmt.Sprintf("%s%c", stem[len(stem)-1], nextChar); checker.Prefix(newWord) {
-					var newStem []string
-					if len(stem) > 1 {
-						newStem = append([]string{}, stem[0:len(stem)-1]...)
-					}
-					newStem = append(newStem, newWord)
-
-					newStems = append(newStems, newStem)
-					if multiWord && checker.Valid(newWord) && len(newWord) >= minLength {
-						newStems = append(newStems, append(append([]string{}, newStem...), ""))
-					}
-				}
-			}
+		letters := pattern.letters(state)
+		if letters == 0 || depth >= max(maxPatternMatchLength, pattern.MinLength()) {
+			return true
 		}
 
-		maxLength = offset
-		stems = newStems
-	}
+		for nextChar := byte('a'); nextChar <= 'z'; nextChar++ {
+			if letters&(1<<(nextChar-'a')) == 0 {
+				continue
+			}
 
-	var res []string
-	for s := range stems {
-		stem := stems[s]
-		if len(stem[len(stem)-1]) >= minLength && checker.Valid(stem[len(stem)-1]) {
-			res = append(res, strings.Join(stems[s], " "))
+			next := fmt.Sprintf("%s%c", current, nextChar)
+			if !options.allowsPrefix(len(words), len(next)) || !checker.Prefix(next) {
+				continue
+			}
+
+			nextState := pattern.step(state, nextChar)
+			if !search(depth+1, nextState, next) {
+				return false
+			}
+
+			if multiWord && pattern.letters(nextState) != 0 && options.allowsWord(len(words), len(next)) && options.allowsMoreWords(len(words)+1) && checker.Valid(next) {
+				words = append(words, next)
+				ok := search(depth+1, nextState, "")
+				words = words[:len(words)-1]
+				if !ok {
+					return false
+				}
+			}
 		}
+		return true
 	}
-	sort.Strings(res)
-	return res, maxLength, nil
+
+	search(0, pattern.start(), "")
 }
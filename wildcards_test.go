@@ -2,7 +2,10 @@ package kowalski
 
 import (
 	"context"
+	"iter"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -51,9 +54,170 @@ func TestMultiMatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := MultiMatch(context.Background(), testChecker, tt.query); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := MultiMatch(context.Background(), testChecker, tt.query, MultiWordOptions{}); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Match() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestMatchSeq(t *testing.T) {
+	var got []string
+	for word := range MatchSeq(context.Background(), testChecker, "???") {
+		got = append(got, word)
+		if len(got) == 2 {
+			break
+		}
+	}
+
+	if want := []string{"bar", "baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchSeq() = %v, want %v", got, want)
+	}
+}
+
+func TestMatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Match(ctx, testChecker, "???"); err != context.Canceled {
+		t.Errorf("Match() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestMultiplexMatchLimit(t *testing.T) {
+	got, err := MultiplexMatch(context.Background(), []Dictionary{testChecker, testChecker}, "???", Limit(2))
+	if err != nil {
+		t.Fatalf("MultiplexMatch() error = %v", err)
+	}
+
+	want := [][]string{{"bar", "baz"}, {"bar", "baz"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiplexMatch() = %v, want %v", got, want)
+	}
+}
+
+func TestMultiMatchOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		options MultiWordOptions
+		want    []string
+	}{
+		{"min word length", "??????", MultiWordOptions{MinWordLength: 3}, []string{"bar bar", "bar baz", "bar foo", "baz bar", "baz baz", "baz foo", "foo bar", "foo baz", "foo foo"}},
+		{"max words", "??????", MultiWordOptions{MinWordLength: 3, MaxWords: 1}, nil},
+		{"required word", "??????", MultiWordOptions{Required: []string{"foo"}}, []string{"bar foo", "baz foo", "foo bar", "foo baz", "foo foo"}},
+		{"required word repeated", "??????", MultiWordOptions{Required: []string{"foo", "foo"}}, []string{"foo foo"}},
+		{"enumeration", "f??b??", MultiWordOptions{Enumeration: mustParseEnumeration("(3-3)")}, []string{"foo-bar", "foo-baz"}},
+		{"enumeration without pattern", "", MultiWordOptions{Enumeration: mustParseEnumeration("(4,3)")}, []string{"quux bar", "quux baz", "quux foo"}},
+		{"enumeration with star", "*z", MultiWordOptions{Enumeration: mustParseEnumeration("(4,3)")}, []string{"quux baz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := MultiMatch(context.Background(), testChecker, tt.query, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MultiMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiMatchEnumerationMismatch(t *testing.T) {
+	for _, pattern := range []string{"f??b?", "f??b???", "f??b???*"} {
+		if _, err := MultiMatch(context.Background(), testChecker, pattern, MultiWordOptions{Enumeration: mustParseEnumeration("(3-3)")}); err == nil {
+			t.Errorf("MultiMatch(%q) returned no error for a pattern that doesn't fit the enumeration", pattern)
+		}
+	}
+}
+
+func TestMatchPatterns(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("cat\ncats\ncot\ncut\ncabinet\nact\n"))
+
+	tests := []struct {
+		name    string
+		checker Dictionary
+		pattern string
+		want    []string
+	}{
+		{"star with spell checker", testChecker, "*x", []string{"quux"}},
+		{"star with word list", list, "c*t", []string{"cabinet", "cat", "cot", "cut"}},
+		{"vowel and consonant", list, "#@#", []string{"cat", "cot", "cut"}},
+		{"character class", list, "c[ao]t", []string{"cat", "cot"}},
+		{"negated character class", testChecker, "ba[^r]", []string{"baz"}},
+		{"leading wildcard", list, "?ut", []string{"cut"}},
+		{"leading star", list, "*ts", []string{"cats"}},
+		{"stars either side", list, "*bin*", []string{"cabinet"}},
+		{"no matches", list, "?x*", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := Match(context.Background(), tt.checker, tt.pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchInvalidPattern(t *testing.T) {
+	if _, err := Match(context.Background(), testChecker, "c[at"); err == nil {
+		t.Errorf("Match() expected error for invalid pattern")
+	}
+}
+
+func TestMultiMatchPatterns(t *testing.T) {
+	got, _ := MultiMatch(context.Background(), testChecker, "f*z", MultiWordOptions{MinWordLength: 3, MaxWords: 2})
+	if want := []string{"foo baz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiMatch() = %v, want %v", got, want)
+	}
+}
+
+func TestMultiplexMatchRankedLimit(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("aaa\t1\nbbb\t5\nccc\t10\n"))
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{1, []string{"ccc"}},
+		{2, []string{"ccc", "bbb"}},
+		{3, []string{"ccc", "bbb", "aaa"}},
+	}
+
+	for _, tt := range tests {
+		got, err := MultiplexMatch(context.Background(), []Dictionary{list}, "???", Ranked, Limit(tt.limit))
+		if err != nil {
+			t.Fatalf("MultiplexMatch() error = %v", err)
+		}
+		if want := [][]string{tt.want}; !reflect.DeepEqual(got, want) {
+			t.Errorf("MultiplexMatch(Limit(%d)) = %v, want %v", tt.limit, got, want)
+		}
+	}
+}
+
+func TestMultiplexRankedLimitStopsUnweightedSolvers(t *testing.T) {
+	list, _ := CreateWordList(strings.NewReader("aaa\t1\nbbb\t5\nccc\t10\n"))
+	checkers := []Dictionary{list, testChecker}
+
+	produced := make([]int, len(checkers))
+	got, err := multiplexSeq(context.Background(), checkers, func(checker Dictionary) iter.Seq[string] {
+		i := slices.Index(checkers, checker)
+		return func(yield func(string) bool) {
+			for _, word := range []string{"aaa", "bbb", "ccc"} {
+				produced[i]++
+				if !yield(word) {
+					return
+				}
+			}
+		}
+	}, []MultiplexOption{Ranked, Limit(1)})
+	if err != nil {
+		t.Fatalf("multiplexSeq() error = %v", err)
+	}
+
+	if want := [][]string{{"ccc"}, {"aaa"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("multiplexSeq() = %v, want %v", got, want)
+	}
+	if want := []int{3, 1}; !reflect.DeepEqual(produced, want) {
+		t.Errorf("multiplexSeq() produced %v results, want %v", produced, want)
+	}
+}
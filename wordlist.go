@@ -0,0 +1,182 @@
+package kowalski
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/blevesearch/vellum"
+)
+
+// WordList is an exact Dictionary backed by a finite state transducer. Unlike SpellChecker it never returns false
+// positives, but its serialised form is typically larger.
+type WordList struct {
+	data []byte
+	fst  *vellum.FST
+	info ModelInfo
+}
+
+// LoadWordList attempts to load a WordList that was previously saved with SaveWordList.
+func LoadWordList(reader io.Reader) (*WordList, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	info, body, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Kind != WordListModel {
+		return nil, fmt.Errorf("model contains a %s, not a word list", info.Kind)
+	}
+
+	return newWordList(body, info)
+}
+
+// SaveWordList serialises the given word list and writes it to the writer.
+// It can later be restored with LoadWordList.
+func SaveWordList(writer io.Writer, list *WordList) error {
+	return encodeModel(writer, list.info, list.data)
+}
+
+// CreateWordList creates a new WordList by reading words line-by-line from the given reader. Each line may
+// optionally contain a frequency after a tab character (e.g. "word\t1234"), which can later be retrieved with
+// Frequency and is used to rank results.
+func CreateWordList(reader io.Reader, opts ...ModelOption) (*WordList, error) {
+	o, err := applyModelOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	words, frequencies, err := readWords(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	builder, err := vellum.New(buffer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range words {
+		if err := builder.Insert([]byte(words[i]), frequencies[words[i]]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := builder.Close(); err != nil {
+		return nil, err
+	}
+
+	return newWordList(buffer.Bytes(), ModelInfo{
+		Version:   modelFormatVersion,
+		Kind:      WordListModel,
+		Source:    o.source,
+		WordCount: len(words),
+		Created:   time.Now().UTC().Truncate(time.Second),
+	})
+}
+
+func newWordList(data []byte, info ModelInfo) (*WordList, error) {
+	f, err := vellum.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WordList{
+		data: data,
+		fst:  f,
+		info: info,
+	}, nil
+}
+
+// Info returns metadata describing how the WordList was built.
+func (l *WordList) Info() ModelInfo {
+	return l.info
+}
+
+// Frequency returns the frequency recorded for the given word when the list was created, or zero if the word is not
+// in the list or no frequency was given.
+func (l *WordList) Frequency(word string) uint64 {
+	frequency, _, err := l.fst.Get([]byte(word))
+	if err != nil {
+		return 0
+	}
+	return frequency
+}
+
+// Valid determines whether the given word was in the word list used to create this WordList.
+func (l *WordList) Valid(word string) bool {
+	addr, ok := l.walk(word)
+	return ok && l.fst.IsMatch(addr)
+}
+
+// Prefix determines whether the given string is a prefix of any word in the word list.
+func (l *WordList) Prefix(prefix string) bool {
+	_, ok := l.walk(prefix)
+	return ok
+}
+
+// Words iterates over all words in the list that start with the given prefix, in lexicographical order. If limit is
+// greater than zero, at most that many words will be returned.
+func (l *WordList) Words(prefix string, limit int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		iterator, err := l.fst.Iterator([]byte(prefix), prefixEnd([]byte(prefix)))
+		for count := 0; err == nil && (limit <= 0 || count < limit); count++ {
+			key, _ := iterator.Current()
+			if !yield(string(key)) {
+				return
+			}
+			err = iterator.Next()
+		}
+	}
+}
+
+// search iterates over all words in the list accepted by the automaton, in lexicographical order.
+func (l *WordList) search(automaton vellum.Automaton) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		iterator, err := l.fst.Search(automaton, nil, nil)
+		for err == nil {
+			key, _ := iterator.Current()
+			if !yield(string(key)) {
+				return
+			}
+			err = iterator.Next()
+		}
+	}
+}
+
+// Len returns the number of words in the list.
+func (l *WordList) Len() int {
+	return l.fst.Len()
+}
+
+// walk follows the transitions for each byte of the input, returning the final state and whether it was reachable.
+func (l *WordList) walk(input string) (int, bool) {
+	addr := l.fst.Start()
+	for i := 0; i < len(input); i++ {
+		addr = l.fst.Accept(addr, input[i])
+		if !l.fst.CanMatch(addr) {
+			return 0, false
+		}
+	}
+	return addr, true
+}
+
+// prefixEnd returns the smallest key that is greater than every key starting with prefix, or nil if there is no such
+// key (i.e. the prefix is empty or consists solely of 0xff bytes).
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package kowalski
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testWordList *WordList
+
+func init() {
+	f, _ := os.Open("testdata/test_words.txt")
+	defer f.Close()
+	testWordList, _ = CreateWordList(f)
+}
+
+func TestCreateWordList(t *testing.T) {
+	tests := []struct {
+		word   string
+		valid  bool
+		prefix bool
+	}{
+		{"foo", true, true},
+		{"bar", true, true},
+		{"baz", true, true},
+		{"quux", true, true},
+		{"ba", false, true},
+		{"b", false, true},
+		{"ab", false, false},
+		{"fooo", false, false},
+		{"quuxx", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := testWordList.Valid(tt.word); got != tt.valid {
+				t.Errorf("Valid() = %v, want %v", got, tt.valid)
+			}
+			if got := testWordList.Prefix(tt.word); got != tt.prefix {
+				t.Errorf("Prefix() = %v, want %v", got, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestSaveLoadWordList(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := SaveWordList(buffer, testWordList); err != nil {
+		t.Errorf("SaveWordList() failed to save word list: %v", err)
+	}
+
+	saved, err := LoadWordList(buffer)
+	if err != nil {
+		t.Fatalf("Failed to load saved word list: %v", err)
+	}
+
+	for _, word := range []string{"foo", "bar", "baz", "quux"} {
+		if !saved.Valid(word) {
+			t.Errorf("Saved word list lost word %s", word)
+		}
+	}
+}
+
+func TestWordListMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"no match", "fr?", nil},
+		{"exact match", "foo", []string{"foo"}},
+		{"exact match with all wildcards", "????", []string{"quux"}},
+		{"multiple matches with one wildcard", "ba?", []string{"bar", "baz"}},
+		{"multiple matches with all wildcards", "???", []string{"bar", "baz", "foo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := Match(context.Background(), testWordList, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordListWords(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"all words", "", 0, []string{"bar", "baz", "foo", "quux"}},
+		{"limited", "", 2, []string{"bar", "baz"}},
+		{"prefix", "ba", 0, []string{"bar", "baz"}},
+		{"whole word prefix", "foo", 0, []string{"foo"}},
+		{"no matches", "x", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for word := range testWordList.Words(tt.prefix, tt.limit) {
+				got = append(got, word)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := testWordList.Len(); got != 4 {
+		t.Errorf("Len() = %d, want 4", got)
+	}
+}
+
+func TestWordListFrequency(t *testing.T) {
+	list, err := CreateWordList(strings.NewReader("foo\t10\nBar\t20\nbar\t5\nbaz\n"))
+	if err != nil {
+		t.Fatalf("CreateWordList() error = %v", err)
+	}
+
+	tests := []struct {
+		word string
+		want uint64
+	}{
+		{"foo", 10},
+		{"bar", 25},
+		{"baz", 0},
+		{"quux", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := list.Frequency(tt.word); got != tt.want {
+				t.Errorf("Frequency() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
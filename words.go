@@ -1,48 +1,64 @@
 package kowalski
 
 import (
+	"context"
+	"iter"
+	"slices"
 	"strings"
 )
 
 // FindWords attempts to find substrings of the input that are valid words according to the checker.
 // Duplicates may be present in the output if they occur at multiple positions.
-func FindWords(checker *SpellChecker, input string) []string {
+func FindWords(checker Dictionary, input string) []string {
 	var res []string
 
-	findWords(checker, input, func(start, end int) {
+	findWords(checker, input, func(start, end int) bool {
 		res = append(res, input[start:end])
+		return true
 	})
 
 	return res
 }
 
-// findWords finds all substrings of the given input, calling func with their start and end offsets.
-func findWords(checker *SpellChecker, input string, fn func(start, end int)) {
+// findWords finds all substrings of the given input, calling func with their start and end offsets. If fn returns
+// false, no further substrings will be checked and findWords will return false.
+func findWords(checker Dictionary, input string, fn func(start, end int) bool) bool {
 	lower := strings.ToLower(input)
 	for i := 0; i < len(input); i++ {
 		for j := i + 1; j < len(input)+1 && checker.Prefix(lower[i:j]); j++ {
-			if checker.Valid(lower[i:j]) {
-				fn(i, j)
+			if checker.Valid(lower[i:j]) && !fn(i, j) {
+				return false
 			}
 		}
 	}
+	return true
 }
 
 // WordSearch returns all words found by FindWords in the input word search grid. Words may occur horizontally,
 // vertically or diagonally, and may read in either direction. If a word is found multiple times in different
 // places it will be returned multiple times.
-func WordSearch(checker *SpellChecker, input []string) []string {
-	var res []string
-	lines := wordSearchLines(input)
-	for i := range lines {
-		words := FindWords(checker, lines[i])
-		for j := range words {
-			if len(words[j]) >= 4 {
-				res = append(res, words[j])
+func WordSearch(checker Dictionary, input []string) []string {
+	return slices.Collect(WordSearchSeq(context.Background(), checker, input))
+}
+
+// WordSearchSeq returns an iterator over the words that WordSearch would return. Words are yielded as they are
+// found; iteration stops early if the context is cancelled, in which case callers should check ctx.Err().
+func WordSearchSeq(ctx context.Context, checker Dictionary, input []string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		lines := wordSearchLines(input)
+		for i := range lines {
+			if ctx.Err() != nil {
+				return
+			}
+
+			ok := findWords(checker, lines[i], func(start, end int) bool {
+				return end-start < 4 || yield(lines[i][start:end])
+			})
+			if !ok {
+				return
 			}
 		}
 	}
-	return res
 }
 
 func wordSearchLines(input []string) []string {